@@ -0,0 +1,76 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type ActivityCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	activeActivities *prometheus.GaugeVec
+	activityProgress *prometheus.GaugeVec
+	activitiesTotal  *prometheus.Desc
+}
+
+func NewActivityCollector(c *plex.PlexClient, l *log.Entry) *ActivityCollector {
+	return &ActivityCollector{
+		Logger: l,
+		client: c,
+
+		activeActivities: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "activities",
+				Name:      "active_count",
+				Help:      "Number of background activities running on the Plex server",
+			},
+			[]string{"type"},
+		),
+		activityProgress: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "activity",
+				Name:      "progress_percent",
+				Help:      "Progress of a background activity running on the Plex server",
+			},
+			[]string{"type", "title"},
+		),
+		activitiesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("plex", "activities", "total"),
+			"Number of background activities seen on the Plex server",
+			[]string{"type"}, nil,
+		),
+	}
+}
+
+func (c *ActivityCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.activeActivities.Describe(ch)
+	c.activityProgress.Describe(ch)
+	ch <- c.activitiesTotal
+}
+
+func (c *ActivityCollector) Collect(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetActivityMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve activity metrics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("Activity metrics: %#v", v)
+	c.activeActivities.Reset()
+	c.activityProgress.Reset()
+	for _, a := range v.Active {
+		c.activeActivities.WithLabelValues(a.Type).Inc()
+		c.activityProgress.WithLabelValues(a.Type, a.Title).Set(float64(a.Progress))
+	}
+
+	for t, count := range v.Seen {
+		ch <- prometheus.MustNewConstMetric(c.activitiesTotal, prometheus.CounterValue, count, t)
+	}
+
+	c.activeActivities.Collect(ch)
+	c.activityProgress.Collect(ch)
+}
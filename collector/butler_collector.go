@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type ButlerCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	taskEnabled  *prometheus.GaugeVec
+	taskInterval *prometheus.GaugeVec
+	taskRunning  *prometheus.GaugeVec
+}
+
+func NewButlerCollector(c *plex.PlexClient, l *log.Entry) *ButlerCollector {
+	return &ButlerCollector{
+		Logger: l,
+		client: c,
+
+		taskEnabled: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "butler",
+				Name:      "task_enabled",
+				Help:      "Whether a Plex scheduled maintenance task is enabled",
+			},
+			[]string{"name", "title"},
+		),
+		taskInterval: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "butler",
+				Name:      "task_interval_seconds",
+				Help:      "Interval between runs of a Plex scheduled maintenance task",
+			},
+			[]string{"name", "title"},
+		),
+		taskRunning: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "butler",
+				Name:      "task_running",
+				Help:      "Whether a Plex scheduled maintenance task is running, for tasks which report an activity",
+			},
+			[]string{"name", "title"},
+		),
+	}
+}
+
+func (c *ButlerCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.taskEnabled.Describe(ch)
+	c.taskInterval.Describe(ch)
+	c.taskRunning.Describe(ch)
+}
+
+func (c *ButlerCollector) Collect(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetButlerMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve butler metrics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("Butler metrics: %#v", v)
+	c.taskEnabled.Reset()
+	c.taskInterval.Reset()
+	c.taskRunning.Reset()
+	for _, t := range v {
+		c.taskEnabled.WithLabelValues(t.Name, t.Title).Set(boolToFloat(t.Enabled))
+		c.taskInterval.WithLabelValues(t.Name, t.Title).Set(t.Interval.Seconds())
+		if t.ReportsActivity {
+			c.taskRunning.WithLabelValues(t.Name, t.Title).Set(boolToFloat(t.Running))
+		}
+	}
+
+	c.taskEnabled.Collect(ch)
+	c.taskInterval.Collect(ch)
+	c.taskRunning.Collect(ch)
+}
@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type ConversionCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	queued     prometheus.Gauge
+	queueBytes prometheus.Gauge
+	processing prometheus.Gauge
+}
+
+func NewConversionCollector(c *plex.PlexClient, l *log.Entry) *ConversionCollector {
+	return &ConversionCollector{
+		Logger: l,
+		client: c,
+
+		queued: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "conversion",
+				Name:      "queue_items",
+				Help:      "Number of items in the Plex conversion queue",
+			},
+		),
+		queueBytes: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "conversion",
+				Name:      "queue_bytes",
+				Help:      "Total size of the source media in the Plex conversion queue",
+			},
+		),
+		processing: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "conversion",
+				Name:      "processing_items",
+				Help:      "Number of items being converted by the Plex transcoder",
+			},
+		),
+	}
+}
+
+func (c *ConversionCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.queued.Describe(ch)
+	c.queueBytes.Describe(ch)
+	c.processing.Describe(ch)
+}
+
+func (c *ConversionCollector) Collect(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetConversionMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve conversion metrics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("Conversion metrics: %#v", v)
+	c.queued.Set(float64(v.Queued))
+	c.queueBytes.Set(float64(v.QueueBytes))
+	c.processing.Set(float64(v.Processing))
+
+	c.queued.Collect(ch)
+	c.queueBytes.Collect(ch)
+	c.processing.Collect(ch)
+}
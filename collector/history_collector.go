@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type HistoryCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	playsTotal *prometheus.Desc
+}
+
+func NewHistoryCollector(c *plex.PlexClient, l *log.Entry) *HistoryCollector {
+	playLabels := []string{"device", "section", "media_type"}
+	if c.UserLabels() {
+		playLabels = append(playLabels, "account")
+	}
+
+	return &HistoryCollector{
+		Logger: l,
+		client: c,
+
+		playsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("plex", "history", "plays_total"),
+			"Number of plays recorded in the Plex watch history",
+			playLabels, nil,
+		),
+	}
+}
+
+func (c *HistoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.playsTotal
+}
+
+func (c *HistoryCollector) Collect(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetHistoryMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve history metrics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("History metrics: %#v", v)
+	for _, h := range v {
+		labels := []string{h.Device, h.Section, h.MediaType}
+		if c.client.UserLabels() {
+			labels = append(labels, h.Account)
+		}
+		ch <- prometheus.MustNewConstMetric(c.playsTotal, prometheus.CounterValue, h.Plays, labels...)
+	}
+}
@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type LibraryHealthCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	healthItems *prometheus.GaugeVec
+}
+
+func NewLibraryHealthCollector(c *plex.PlexClient, l *log.Entry) *LibraryHealthCollector {
+	return &LibraryHealthCollector{
+		Logger: l,
+		client: c,
+
+		healthItems: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_health_items",
+				Help:      "Number of items in a library section with a metadata issue, or without intro or credits markers",
+			},
+			[]string{"name", "type", "issue"},
+		),
+	}
+}
+
+func (c *LibraryHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.healthItems.Describe(ch)
+}
+
+func (c *LibraryHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	v := c.client.GetLibraryHealthMetrics()
+
+	c.Logger.Tracef("Library health metrics: %#v", v)
+	c.healthItems.Reset()
+	for _, l := range v {
+		for issue, count := range l.Issues {
+			c.healthItems.WithLabelValues(l.Name, l.Type, issue).Set(float64(count))
+		}
+	}
+
+	c.healthItems.Collect(ch)
+}
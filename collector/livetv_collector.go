@@ -0,0 +1,143 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type LiveTVCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	dvrInfo            *prometheus.GaugeVec
+	deviceUp           *prometheus.GaugeVec
+	deviceTuners       *prometheus.GaugeVec
+	deviceTunersInUse  *prometheus.GaugeVec
+	recordingRules     prometheus.Gauge
+	recordings         *prometheus.GaugeVec
+	recordingConflicts prometheus.Gauge
+}
+
+func NewLiveTVCollector(c *plex.PlexClient, l *log.Entry) *LiveTVCollector {
+	deviceLabels := []string{"dvr", "device", "make", "model"}
+
+	return &LiveTVCollector{
+		Logger: l,
+		client: c,
+
+		dvrInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "dvr",
+				Name:      "info",
+				Help:      "Information about a DVR configured on the Plex server",
+			},
+			[]string{"dvr", "lineup", "language"},
+		),
+		deviceUp: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "dvr",
+				Name:      "device_up",
+				Help:      "Whether a DVR tuner device is reachable by the Plex server",
+			},
+			deviceLabels,
+		),
+		deviceTuners: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "dvr",
+				Name:      "device_tuners",
+				Help:      "Number of tuners in a DVR tuner device",
+			},
+			deviceLabels,
+		),
+		deviceTunersInUse: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "dvr",
+				Name:      "device_tuners_in_use",
+				Help:      "Number of tuners in a DVR tuner device in use by recordings",
+			},
+			deviceLabels,
+		),
+		recordingRules: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "dvr",
+				Name:      "recording_rules",
+				Help:      "Number of recording rules configured on the Plex server",
+			},
+		),
+		recordings: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "dvr",
+				Name:      "recordings",
+				Help:      "Number of scheduled and in progress recordings by status",
+			},
+			[]string{"status"},
+		),
+		recordingConflicts: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "dvr",
+				Name:      "recording_conflicts",
+				Help:      "Number of scheduled recordings that overlap more recordings than there are tuners",
+			},
+		),
+	}
+}
+
+func (c *LiveTVCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.dvrInfo.Describe(ch)
+	c.deviceUp.Describe(ch)
+	c.deviceTuners.Describe(ch)
+	c.deviceTunersInUse.Describe(ch)
+	c.recordingRules.Describe(ch)
+	c.recordings.Describe(ch)
+	c.recordingConflicts.Describe(ch)
+}
+
+func (c *LiveTVCollector) Collect(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetLiveTVMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve live TV metrics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("Live TV metrics: %#v", v)
+	c.dvrInfo.Reset()
+	for _, d := range v.DVRs {
+		c.dvrInfo.WithLabelValues(d.Key, d.Lineup, d.Language).Set(1)
+	}
+
+	c.deviceUp.Reset()
+	c.deviceTuners.Reset()
+	c.deviceTunersInUse.Reset()
+	for _, d := range v.Devices {
+		c.deviceUp.WithLabelValues(d.DVR, d.Device, d.Make, d.Model).Set(boolToFloat(d.Up))
+		c.deviceTuners.WithLabelValues(d.DVR, d.Device, d.Make, d.Model).Set(float64(d.Tuners))
+		c.deviceTunersInUse.WithLabelValues(d.DVR, d.Device, d.Make, d.Model).Set(float64(d.TunersInUse))
+	}
+
+	c.recordingRules.Set(float64(v.RecordingRules))
+	c.recordings.Reset()
+	for status, count := range v.Recordings {
+		c.recordings.WithLabelValues(status).Set(float64(count))
+	}
+	if v.Tuners > 0 {
+		c.recordingConflicts.Set(float64(v.Conflicts))
+	}
+
+	c.dvrInfo.Collect(ch)
+	c.deviceUp.Collect(ch)
+	c.deviceTuners.Collect(ch)
+	c.deviceTunersInUse.Collect(ch)
+	c.recordingRules.Collect(ch)
+	c.recordings.Collect(ch)
+	if v.Tuners > 0 {
+		c.recordingConflicts.Collect(ch)
+	}
+}
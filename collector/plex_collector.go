@@ -1,6 +1,8 @@
 package collector
 
 import (
+	"strconv"
+
 	"github.com/frebib/plex-exporter/plex"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
@@ -11,13 +13,47 @@ type PlexCollector struct {
 	client *plex.PlexClient
 
 	serverInfo         *prometheus.GaugeVec
+	serverPlatform     *prometheus.GaugeVec
+	serverPlexPass     prometheus.Gauge
+	serverCapability   *prometheus.GaugeVec
+	serverFeature      *prometheus.GaugeVec
+	transcoderSessions prometheus.Gauge
 	activeSessionCount *prometheus.GaugeVec
+	sessionsBandwidth  *prometheus.GaugeVec
+	sessionBandwidth   *prometheus.GaugeVec
+	sessionsByDecision *prometheus.GaugeVec
 	libraryMetric      *prometheus.GaugeVec
+	libraryInfo        *prometheus.GaugeVec
+	libraryLocation    *prometheus.GaugeVec
+	libraryScannedAt   *prometheus.GaugeVec
+	libraryRefreshing  *prometheus.GaugeVec
+	libraryItems       *prometheus.GaugeVec
+	libraryCollections *prometheus.GaugeVec
+	playlistCount      *prometheus.GaugeVec
+	libraryNewestItem  *prometheus.GaugeVec
+	libraryItemsAdded  *prometheus.Desc
+	libraryBytes       *prometheus.GaugeVec
+	libraryDuration    *prometheus.GaugeVec
+	libraryQuality     *prometheus.GaugeVec
+	libraryWatchState  *prometheus.GaugeVec
+	libraryViews       *prometheus.GaugeVec
 	playerMetric       *prometheus.GaugeVec
+	sessionsByUser     *prometheus.GaugeVec
+	playsTotal         *prometheus.Desc
+	watchSecondsTotal  *prometheus.Desc
+	stateSecondsTotal  *prometheus.Desc
+	bufferingTotal     *prometheus.Desc
 }
 
 func NewPlexCollector(c *plex.PlexClient, l *log.Entry) *PlexCollector {
-	return &PlexCollector{
+	playerLabels := []string{"device", "platform", "profile", "state", "local", "relayed", "secure"}
+	playLabels := []string{"section", "media_type"}
+	if c.UserLabels() {
+		playerLabels = append(playerLabels, "user")
+		playLabels = append(playLabels, "user")
+	}
+
+	pc := &PlexCollector{
 		Logger: l,
 		client: c,
 
@@ -30,6 +66,49 @@ func NewPlexCollector(c *plex.PlexClient, l *log.Entry) *PlexCollector {
 			},
 			[]string{"version", "platform"},
 		),
+		serverPlatform: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "server",
+				Name:      "platform_info",
+				Help:      "Information about the Plex server's platform",
+			},
+			[]string{"platform", "platform_version"},
+		),
+		serverPlexPass: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "server",
+				Name:      "plex_pass_subscription",
+				Help:      "Whether the Plex server owner has an active Plex Pass subscription",
+			},
+		),
+		serverCapability: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "server",
+				Name:      "capability",
+				Help:      "Whether the Plex server has a capability enabled",
+			},
+			[]string{"capability"},
+		),
+		serverFeature: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "server",
+				Name:      "feature",
+				Help:      "Features supported by the Plex server's media providers",
+			},
+			[]string{"provider", "feature"},
+		),
+		transcoderSessions: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "server",
+				Name:      "transcoder_active_video_sessions",
+				Help:      "Number of active video transcoder sessions reported by the Plex server",
+			},
+		),
 		activeSessionCount: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "plex",
@@ -39,6 +118,33 @@ func NewPlexCollector(c *plex.PlexClient, l *log.Entry) *PlexCollector {
 			},
 			[]string{},
 		),
+		sessionsBandwidth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "sessions",
+				Name:      "bandwidth_kbps",
+				Help:      "Total bandwidth of active Plex sessions by network location",
+			},
+			[]string{"location"},
+		),
+		sessionBandwidth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "session",
+				Name:      "bandwidth_kbps",
+				Help:      "Bandwidth of an active Plex session",
+			},
+			[]string{"session_id", "device", "platform", "location"},
+		),
+		sessionsByDecision: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "sessions",
+				Name:      "by_decision",
+				Help:      "Number of active Plex sessions by play decision, media type and resolution",
+			},
+			[]string{"decision", "media_type", "source_resolution", "target_resolution"},
+		),
 		libraryMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "plex",
@@ -48,6 +154,128 @@ func NewPlexCollector(c *plex.PlexClient, l *log.Entry) *PlexCollector {
 			},
 			[]string{"name", "type"},
 		),
+		libraryInfo: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_info",
+				Help:      "Information about a library section",
+			},
+			[]string{"name", "type", "agent", "scanner", "language", "uuid"},
+		),
+		libraryLocation: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_location_info",
+				Help:      "Paths of the folders in a library section",
+			},
+			[]string{"name", "type", "path"},
+		),
+		libraryScannedAt: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_last_scanned_timestamp_seconds",
+				Help:      "Time a library section was last scanned",
+			},
+			[]string{"name", "type"},
+		),
+		libraryRefreshing: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_refreshing",
+				Help:      "Whether a library section is being scanned",
+			},
+			[]string{"name", "type"},
+		),
+		libraryItems: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_items",
+				Help:      "Number of items of each type in a library section",
+			},
+			[]string{"name", "type", "item_type"},
+		),
+		libraryCollections: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_collections",
+				Help:      "Number of collections in a library section",
+			},
+			[]string{"name", "type"},
+		),
+		playlistCount: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "playlists",
+				Name:      "count",
+				Help:      "Number of playlists on the Plex server",
+			},
+			[]string{"playlist_type", "smart"},
+		),
+		libraryNewestItem: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_newest_item_timestamp_seconds",
+				Help:      "Time the newest item was added to a library section",
+			},
+			[]string{"name", "type"},
+		),
+		libraryItemsAdded: prometheus.NewDesc(
+			prometheus.BuildFQName("plex", "library", "section_items_added_total"),
+			"Number of new items detected in a library section",
+			[]string{"name", "type"}, nil,
+		),
+		libraryBytes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_bytes",
+				Help:      "Total size of the media files in a library section",
+			},
+			[]string{"name", "type"},
+		),
+		libraryDuration: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_duration_seconds",
+				Help:      "Total runtime of the items in a library section",
+			},
+			[]string{"name", "type"},
+		),
+		libraryQuality: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_quality_items",
+				Help:      "Number of video items in a library section by media quality attribute",
+			},
+			[]string{"name", "type", "dimension", "value"},
+		),
+		libraryWatchState: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_watch_state_items",
+				Help:      "Number of items in a library section by watch state, items in progress are not counted as watched or unwatched",
+			},
+			[]string{"name", "type", "state"},
+		),
+		libraryViews: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "library",
+				Name:      "section_view_count",
+				Help:      "Total number of times the items in a library section have been watched",
+			},
+			[]string{"name", "type"},
+		),
 		playerMetric: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: "plex",
@@ -55,16 +283,78 @@ func NewPlexCollector(c *plex.PlexClient, l *log.Entry) *PlexCollector {
 				Name:      "count",
 				Help:      "Details about current players connected to Plex",
 			},
-			[]string{"device", "platform", "profile", "state", "local", "relayed", "secure"},
+			playerLabels,
+		),
+		playsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("plex", "", "plays_total"),
+			"Number of completed Plex plays",
+			playLabels, nil,
+		),
+		watchSecondsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("plex", "", "watch_seconds_total"),
+			"Time spent playing media in Plex sessions",
+			playLabels, nil,
+		),
+		stateSecondsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("plex", "sessions", "state_seconds_total"),
+			"Time spent by Plex sessions in each player state",
+			[]string{"state"}, nil,
+		),
+		bufferingTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("plex", "sessions", "buffering_events_total"),
+			"Number of times a Plex session has started buffering",
+			nil, nil,
 		),
 	}
+
+	if c.UserLabels() {
+		pc.sessionsByUser = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "sessions",
+				Name:      "by_user",
+				Help:      "Number of active Plex sessions per user",
+			},
+			[]string{"user"},
+		)
+	}
+	return pc
 }
 
 func (c *PlexCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.serverInfo.Describe(ch)
+	c.serverPlatform.Describe(ch)
+	c.serverPlexPass.Describe(ch)
+	c.serverCapability.Describe(ch)
+	c.serverFeature.Describe(ch)
+	c.transcoderSessions.Describe(ch)
 	c.activeSessionCount.Describe(ch)
+	c.sessionsBandwidth.Describe(ch)
+	c.sessionBandwidth.Describe(ch)
+	c.sessionsByDecision.Describe(ch)
 	c.libraryMetric.Describe(ch)
+	c.libraryInfo.Describe(ch)
+	c.libraryLocation.Describe(ch)
+	c.libraryScannedAt.Describe(ch)
+	c.libraryRefreshing.Describe(ch)
+	c.libraryItems.Describe(ch)
+	c.libraryCollections.Describe(ch)
+	c.playlistCount.Describe(ch)
+	c.libraryNewestItem.Describe(ch)
+	ch <- c.libraryItemsAdded
+	c.libraryBytes.Describe(ch)
+	c.libraryDuration.Describe(ch)
+	c.libraryQuality.Describe(ch)
+	c.libraryWatchState.Describe(ch)
+	c.libraryViews.Describe(ch)
 	c.playerMetric.Describe(ch)
+	if c.sessionsByUser != nil {
+		c.sessionsByUser.Describe(ch)
+	}
+	ch <- c.playsTotal
+	ch <- c.watchSecondsTotal
+	ch <- c.stateSecondsTotal
+	ch <- c.bufferingTotal
 }
 
 func (c *PlexCollector) Collect(ch chan<- prometheus.Metric) {
@@ -76,19 +366,143 @@ func (c *PlexCollector) Collect(ch chan<- prometheus.Metric) {
 
 	c.Logger.Tracef("Server metrics: %#v", v)
 	c.serverInfo.WithLabelValues(v.Version, v.Platform).Set(1)
+	c.serverPlatform.WithLabelValues(v.Platform, v.PlatformVersion).Set(1)
+	// Capabilities are missing if the server root couldn't be fetched
+	if v.Capabilities != nil {
+		c.serverPlexPass.Set(boolToFloat(v.PlexPass))
+		c.transcoderSessions.Set(float64(v.TranscoderActiveVideoSessions))
+		for capability, enabled := range v.Capabilities {
+			c.serverCapability.WithLabelValues(capability).Set(boolToFloat(enabled))
+		}
+	}
+	c.serverFeature.Reset()
+	for _, f := range v.Features {
+		c.serverFeature.WithLabelValues(f.Provider, f.Feature).Set(1)
+	}
 	c.activeSessionCount.WithLabelValues().Set(float64(v.ActiveSessions))
+	c.sessionsBandwidth.WithLabelValues("lan").Set(float64(v.LANBandwidth))
+	c.sessionsBandwidth.WithLabelValues("wan").Set(float64(v.WANBandwidth))
+
+	c.sessionBandwidth.Reset()
+	c.sessionsByDecision.Reset()
+	for _, s := range v.Sessions {
+		c.sessionBandwidth.WithLabelValues(s.ID, s.Device, s.Platform, s.Location).Set(float64(s.Bandwidth))
+		c.sessionsByDecision.WithLabelValues(s.Decision, s.MediaType, s.SourceResolution, s.TargetResolution).Inc()
+	}
 
 	c.playerMetric.Reset()
+	if c.sessionsByUser != nil {
+		c.sessionsByUser.Reset()
+	}
 	for _, p := range v.Players {
-		c.playerMetric.WithLabelValues(p.Device, p.Platform, p.Profile, p.State, p.Local, p.Relayed, p.Secure).Inc()
+		labels := []string{p.Device, p.Platform, p.Profile, p.State, p.Local, p.Relayed, p.Secure}
+		if c.sessionsByUser != nil {
+			labels = append(labels, p.User)
+			c.sessionsByUser.WithLabelValues(p.User).Inc()
+		}
+		c.playerMetric.WithLabelValues(labels...).Inc()
+	}
+
+	for _, p := range v.Plays {
+		labels := []string{p.Section, p.MediaType}
+		if c.sessionsByUser != nil {
+			labels = append(labels, p.User)
+		}
+		ch <- prometheus.MustNewConstMetric(c.playsTotal, prometheus.CounterValue, p.Plays, labels...)
+		ch <- prometheus.MustNewConstMetric(c.watchSecondsTotal, prometheus.CounterValue, p.WatchSeconds, labels...)
+	}
+
+	for _, s := range v.States {
+		ch <- prometheus.MustNewConstMetric(c.stateSecondsTotal, prometheus.CounterValue, s.Seconds, s.State)
 	}
+	ch <- prometheus.MustNewConstMetric(c.bufferingTotal, prometheus.CounterValue, v.BufferingCount)
 
+	c.playlistCount.Reset()
+	for _, p := range v.Playlists {
+		c.playlistCount.WithLabelValues(p.Type, strconv.FormatBool(p.Smart)).Set(float64(p.Count))
+	}
+
+	c.libraryInfo.Reset()
+	c.libraryLocation.Reset()
+	c.libraryItems.Reset()
+	c.libraryNewestItem.Reset()
+	c.libraryCollections.Reset()
+	c.libraryQuality.Reset()
+	// Sections may share a name and type, so sum their items added to avoid
+	// emitting duplicate counters
+	itemsAdded := make(map[[2]string]float64)
 	for _, l := range v.Libraries {
 		c.libraryMetric.WithLabelValues(l.Name, l.Type).Set(float64(l.Size))
+		if l.Collections != nil {
+			c.libraryCollections.WithLabelValues(l.Name, l.Type).Set(float64(*l.Collections))
+		}
+		c.libraryInfo.WithLabelValues(l.Name, l.Type, l.Agent, l.Scanner, l.Language, l.UUID).Set(1)
+		for _, path := range l.Locations {
+			c.libraryLocation.WithLabelValues(l.Name, l.Type, path).Set(1)
+		}
+		c.libraryScannedAt.WithLabelValues(l.Name, l.Type).Set(float64(l.ScannedAt))
+		c.libraryRefreshing.WithLabelValues(l.Name, l.Type).Set(boolToFloat(l.Refreshing))
+		for itemType, count := range l.Items {
+			c.libraryItems.WithLabelValues(l.Name, l.Type, itemType).Set(float64(count))
+		}
+		if l.NewestItem > 0 {
+			c.libraryNewestItem.WithLabelValues(l.Name, l.Type).Set(float64(l.NewestItem))
+		}
+		itemsAdded[[2]string{l.Name, l.Type}] += l.ItemsAdded
+		if l.Stats != nil {
+			c.libraryBytes.WithLabelValues(l.Name, l.Type).Set(float64(l.Stats.Bytes))
+			c.libraryDuration.WithLabelValues(l.Name, l.Type).Set(l.Stats.Duration.Seconds())
+			for _, q := range l.Stats.Quality {
+				c.libraryQuality.WithLabelValues(l.Name, l.Type, q.Dimension, q.Value).Set(float64(q.Count))
+			}
+			if l.Type != "photo" {
+				c.libraryWatchState.WithLabelValues(l.Name, l.Type, "unwatched").Set(float64(l.Stats.Unwatched))
+				c.libraryWatchState.WithLabelValues(l.Name, l.Type, "in_progress").Set(float64(l.Stats.InProgress))
+				c.libraryWatchState.WithLabelValues(l.Name, l.Type, "watched").Set(float64(l.Stats.Watched))
+				c.libraryViews.WithLabelValues(l.Name, l.Type).Set(float64(l.Stats.ViewCount))
+			}
+		}
+	}
+
+	for l, added := range itemsAdded {
+		ch <- prometheus.MustNewConstMetric(c.libraryItemsAdded, prometheus.CounterValue, added, l[0], l[1])
 	}
 
 	c.serverInfo.Collect(ch)
+	c.serverPlatform.Collect(ch)
+	if v.Capabilities != nil {
+		c.serverPlexPass.Collect(ch)
+		c.serverCapability.Collect(ch)
+		c.transcoderSessions.Collect(ch)
+	}
+	c.serverFeature.Collect(ch)
 	c.activeSessionCount.Collect(ch)
+	c.sessionsBandwidth.Collect(ch)
+	c.sessionBandwidth.Collect(ch)
+	c.sessionsByDecision.Collect(ch)
 	c.libraryMetric.Collect(ch)
+	c.libraryInfo.Collect(ch)
+	c.libraryLocation.Collect(ch)
+	c.libraryScannedAt.Collect(ch)
+	c.libraryRefreshing.Collect(ch)
+	c.libraryItems.Collect(ch)
+	c.libraryCollections.Collect(ch)
+	c.playlistCount.Collect(ch)
+	c.libraryNewestItem.Collect(ch)
+	c.libraryBytes.Collect(ch)
+	c.libraryDuration.Collect(ch)
+	c.libraryQuality.Collect(ch)
+	c.libraryWatchState.Collect(ch)
+	c.libraryViews.Collect(ch)
 	c.playerMetric.Collect(ch)
+	if c.sessionsByUser != nil {
+		c.sessionsByUser.Collect(ch)
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
 }
@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type RemoteAccessCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	mapped   prometheus.Gauge
+	signedIn prometheus.Gauge
+	info     *prometheus.GaugeVec
+}
+
+func NewRemoteAccessCollector(c *plex.PlexClient, l *log.Entry) *RemoteAccessCollector {
+	return &RemoteAccessCollector{
+		Logger: l,
+		client: c,
+
+		mapped: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "remote_access",
+				Name:      "mapped",
+				Help:      "Whether the Plex server's remote access port is mapped",
+			},
+		),
+		signedIn: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "remote_access",
+				Name:      "signed_in",
+				Help:      "Whether the Plex server is signed in to plex.tv",
+			},
+		),
+		info: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "remote_access",
+				Name:      "info",
+				Help:      "Information about the Plex server's remote access state",
+			},
+			[]string{"mapping_state", "mapping_error", "sign_in_state", "public_address", "public_port"},
+		),
+	}
+}
+
+func (c *RemoteAccessCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.mapped.Describe(ch)
+	c.signedIn.Describe(ch)
+	c.info.Describe(ch)
+}
+
+func (c *RemoteAccessCollector) Collect(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetRemoteAccessMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve remote access metrics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("Remote access metrics: %#v", v)
+	c.mapped.Set(boolToFloat(v.MappingState == "mapped"))
+	c.signedIn.Set(boolToFloat(v.SignInState == "ok"))
+
+	c.info.Reset()
+	c.info.WithLabelValues(v.MappingState, v.MappingError, v.SignInState, v.PublicAddress, v.PublicPort).Set(1)
+
+	c.mapped.Collect(ch)
+	c.signedIn.Collect(ch)
+	c.info.Collect(ch)
+}
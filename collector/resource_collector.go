@@ -0,0 +1,83 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type ResourceCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	hostCPU       prometheus.Gauge
+	processCPU    prometheus.Gauge
+	hostMemory    prometheus.Gauge
+	processMemory prometheus.Gauge
+}
+
+func NewResourceCollector(c *plex.PlexClient, l *log.Entry) *ResourceCollector {
+	return &ResourceCollector{
+		Logger: l,
+		client: c,
+
+		hostCPU: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "host",
+				Name:      "cpu_utilization",
+				Help:      "CPU utilisation of the host running Plex, as a percentage",
+			},
+		),
+		processCPU: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "process",
+				Name:      "cpu_utilization",
+				Help:      "CPU utilisation of the Plex Media Server process, as a percentage",
+			},
+		),
+		hostMemory: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "host",
+				Name:      "memory_utilization",
+				Help:      "Memory utilisation of the host running Plex, as a percentage",
+			},
+		),
+		processMemory: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "process",
+				Name:      "memory_utilization",
+				Help:      "Memory utilisation of the Plex Media Server process, as a percentage",
+			},
+		),
+	}
+}
+
+func (c *ResourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.hostCPU.Describe(ch)
+	c.processCPU.Describe(ch)
+	c.hostMemory.Describe(ch)
+	c.processMemory.Describe(ch)
+}
+
+func (c *ResourceCollector) Collect(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetResourceMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve resource metrics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("Resource metrics: %#v", v)
+	c.hostCPU.Set(v.HostCPU)
+	c.processCPU.Set(v.ProcessCPU)
+	c.hostMemory.Set(v.HostMemory)
+	c.processMemory.Set(v.ProcessMemory)
+
+	c.hostCPU.Collect(ch)
+	c.processCPU.Collect(ch)
+	c.hostMemory.Collect(ch)
+	c.processMemory.Collect(ch)
+}
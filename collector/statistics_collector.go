@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type StatisticsCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	bandwidthBytes    *prometheus.Desc
+	playsTotal        *prometheus.Desc
+	watchSecondsTotal *prometheus.Desc
+}
+
+func NewStatisticsCollector(c *plex.PlexClient, l *log.Entry) *StatisticsCollector {
+	bandwidthLabels := []string{"device", "platform", "location"}
+	mediaLabels := []string{"device", "platform", "media_type"}
+	if c.UserLabels() {
+		bandwidthLabels = append(bandwidthLabels, "account")
+		mediaLabels = append(mediaLabels, "account")
+	}
+
+	return &StatisticsCollector{
+		Logger: l,
+		client: c,
+
+		bandwidthBytes: prometheus.NewDesc(
+			prometheus.BuildFQName("plex", "bandwidth", "bytes_total"),
+			"Bytes transferred by Plex, from the server's bandwidth history",
+			bandwidthLabels, nil,
+		),
+		playsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("plex", "statistics", "plays_total"),
+			"Number of plays, from the server's playback statistics",
+			mediaLabels, nil,
+		),
+		watchSecondsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName("plex", "statistics", "watch_seconds_total"),
+			"Time spent playing media, from the server's playback statistics",
+			mediaLabels, nil,
+		),
+	}
+}
+
+func (c *StatisticsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.bandwidthBytes
+	ch <- c.playsTotal
+	ch <- c.watchSecondsTotal
+}
+
+func (c *StatisticsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.collectBandwidth(ch)
+	c.collectMedia(ch)
+}
+
+func (c *StatisticsCollector) collectBandwidth(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetBandwidthMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve bandwidth metrics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("Bandwidth metrics: %#v", v)
+	for _, b := range v {
+		labels := []string{b.Device, b.Platform, b.Location}
+		if c.client.UserLabels() {
+			labels = append(labels, b.Account)
+		}
+		ch <- prometheus.MustNewConstMetric(c.bandwidthBytes, prometheus.CounterValue, b.Bytes, labels...)
+	}
+}
+
+func (c *StatisticsCollector) collectMedia(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetMediaStatsMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve playback statistics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("Playback statistics: %#v", v)
+	for _, m := range v {
+		labels := []string{m.Device, m.Platform, m.MediaType}
+		if c.client.UserLabels() {
+			labels = append(labels, m.Account)
+		}
+		ch <- prometheus.MustNewConstMetric(c.playsTotal, prometheus.CounterValue, m.Plays, labels...)
+		ch <- prometheus.MustNewConstMetric(c.watchSecondsTotal, prometheus.CounterValue, m.WatchSeconds, labels...)
+	}
+}
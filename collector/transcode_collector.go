@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type TranscodeCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	activeTranscodes   *prometheus.GaugeVec
+	transcodeSpeed     *prometheus.GaugeVec
+	transcodeThrottled *prometheus.GaugeVec
+}
+
+func NewTranscodeCollector(c *plex.PlexClient, l *log.Entry) *TranscodeCollector {
+	return &TranscodeCollector{
+		Logger: l,
+		client: c,
+
+		activeTranscodes: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "transcode",
+				Name:      "active_count",
+				Help:      "Number of active Plex transcode sessions",
+			},
+			[]string{"video_decision", "audio_decision", "hw_decoding", "hw_encoding", "container"},
+		),
+		transcodeSpeed: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "transcode",
+				Name:      "speed_ratio",
+				Help:      "Speed of a transcode session relative to realtime playback",
+			},
+			[]string{"session"},
+		),
+		transcodeThrottled: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "transcode",
+				Name:      "throttled",
+				Help:      "Whether a transcode session is throttled",
+			},
+			[]string{"session"},
+		),
+	}
+}
+
+func (c *TranscodeCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.activeTranscodes.Describe(ch)
+	c.transcodeSpeed.Describe(ch)
+	c.transcodeThrottled.Describe(ch)
+}
+
+func (c *TranscodeCollector) Collect(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetTranscodeMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve transcode metrics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("Transcode metrics: %#v", v)
+	c.activeTranscodes.Reset()
+	c.transcodeSpeed.Reset()
+	c.transcodeThrottled.Reset()
+	for _, t := range v {
+		c.activeTranscodes.WithLabelValues(t.VideoDecision, t.AudioDecision, t.HwDecoding, t.HwEncoding, t.Container).Inc()
+		c.transcodeSpeed.WithLabelValues(t.Key).Set(t.Speed)
+		c.transcodeThrottled.WithLabelValues(t.Key).Set(boolToFloat(t.Throttled))
+	}
+
+	c.activeTranscodes.Collect(ch)
+	c.transcodeSpeed.Collect(ch)
+	c.transcodeThrottled.Collect(ch)
+}
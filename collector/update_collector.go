@@ -0,0 +1,51 @@
+package collector
+
+import (
+	"github.com/frebib/plex-exporter/plex"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type UpdateCollector struct {
+	Logger *log.Entry
+	client *plex.PlexClient
+
+	updateAvailable *prometheus.GaugeVec
+}
+
+func NewUpdateCollector(c *plex.PlexClient, l *log.Entry) *UpdateCollector {
+	return &UpdateCollector{
+		Logger: l,
+		client: c,
+
+		updateAvailable: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "plex",
+				Subsystem: "server",
+				Name:      "update_available",
+				Help:      "Whether a newer Plex Media Server release is available",
+			},
+			[]string{"source", "version", "candidate_version"},
+		),
+	}
+}
+
+func (c *UpdateCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.updateAvailable.Describe(ch)
+}
+
+func (c *UpdateCollector) Collect(ch chan<- prometheus.Metric) {
+	v, err := c.client.GetUpdateMetrics()
+	if err != nil {
+		c.Logger.Errorf("Could not retrieve update metrics: %s", err)
+		return
+	}
+
+	c.Logger.Tracef("Update metrics: %#v", v)
+	c.updateAvailable.Reset()
+	for _, u := range v {
+		c.updateAvailable.WithLabelValues(u.Source, u.Version, u.CandidateVersion).Set(boolToFloat(u.Available))
+	}
+
+	c.updateAvailable.Collect(ch)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"time"
 
 	"github.com/urfave/cli"
 	"gopkg.in/yaml.v2"
@@ -17,6 +18,20 @@ type PlexConfig struct {
 	AutoDiscover  bool               `yaml:"autoDiscover" flag:"auto-discover"`
 	Token         string             `yaml:"token" flag:"token"`
 	Servers       []PlexServerConfig `yaml:"servers"`
+
+	// Per-user labels are opt-in as they can expose viewing habits and
+	// increase metric cardinality
+	UserLabels     bool   `yaml:"userLabels" flag:"user-labels"`
+	HashUserLabels bool   `yaml:"hashUserLabels" flag:"hash-user-labels"`
+	UserLabelKey   string `yaml:"userLabelKey" flag:"user-label-key"`
+	MaxUserLabels  int    `yaml:"maxUserLabels" flag:"max-user-labels"`
+
+	// Check plex.tv for new releases, for servers with the updater disabled
+	CheckPlexReleases bool `yaml:"checkPlexReleases" flag:"check-plex-releases"`
+
+	// Interval between crawls of every item in the library, which are too
+	// expensive to run on every scrape
+	LibraryStatsInterval time.Duration `yaml:"libraryStatsInterval" flag:"library-stats-interval"`
 }
 
 type PlexServerConfig struct {
@@ -74,6 +89,20 @@ func MergeConfig(conf *PlexConfig, c *cli.Context) {
 			}
 		}
 
+		if fieldType == reflect.TypeOf(time.Duration(0)) {
+			flagValue := c.Duration(flagName)
+			if flagValue != 0 {
+				confElem.Field(i).SetInt(int64(flagValue))
+			}
+		}
+
+		if fieldType.Kind() == reflect.Int {
+			flagValue := c.Int(flagName)
+			if flagValue != 0 {
+				confElem.Field(i).SetInt(int64(flagValue))
+			}
+		}
+
 		if fieldType.Kind() == reflect.Bool {
 			flagValue := c.Bool(flagName)
 			if flagValue {
@@ -84,14 +84,25 @@ func Run(c *cli.Context) error {
 	for _, server := range serverList {
 		// Create a Plex client
 		clientLogger := log.WithFields(log.Fields{"context": "client", "server": server.Name})
-		client, err := plex.NewPlexClient(server, clientLogger)
+		client, err := plex.NewPlexClient(server, conf, clientLogger)
 
 		// Create the Prometheus collector
 		collectorLogger := log.WithFields(log.Fields{"context": "collector", "server": server.Name})
 		pc := collector.NewPlexCollector(client, collectorLogger)
+		tc := collector.NewTranscodeCollector(client, collectorLogger)
+		rc := collector.NewResourceCollector(client, collectorLogger)
+		sc := collector.NewStatisticsCollector(client, collectorLogger)
+		ac := collector.NewActivityCollector(client, collectorLogger)
+		bc := collector.NewButlerCollector(client, collectorLogger)
+		uc := collector.NewUpdateCollector(client, collectorLogger)
+		rac := collector.NewRemoteAccessCollector(client, collectorLogger)
+		lhc := collector.NewLibraryHealthCollector(client, collectorLogger)
+		hc := collector.NewHistoryCollector(client, collectorLogger)
+		lc := collector.NewLiveTVCollector(client, collectorLogger)
+		cc := collector.NewConversionCollector(client, collectorLogger)
 		prometheus.WrapRegistererWith(
 			prometheus.Labels{"server_name": server.Name, "server_id": server.ID}, reg,
-		).MustRegister(pc)
+		).MustRegister(pc, tc, rc, sc, ac, bc, uc, rac, lhc, hc, lc, cc)
 
 		if err != nil {
 			return err
@@ -188,6 +199,36 @@ func main() {
 			Usage:  "Authentication token for Plex Media Server",
 			EnvVar: "PLEX_TOKEN,TOKEN",
 		},
+		cli.BoolFlag{
+			Name:   "user-labels",
+			Usage:  "Label session metrics with the Plex user",
+			EnvVar: "PLEX_USER_LABELS,USER_LABELS",
+		},
+		cli.BoolFlag{
+			Name:   "hash-user-labels",
+			Usage:  "Hash Plex user names before using them as labels",
+			EnvVar: "PLEX_HASH_USER_LABELS,HASH_USER_LABELS",
+		},
+		cli.StringFlag{
+			Name:   "user-label-key",
+			Usage:  "Secret key for hashing Plex user names, a random key is used if unset",
+			EnvVar: "PLEX_USER_LABEL_KEY,USER_LABEL_KEY",
+		},
+		cli.IntFlag{
+			Name:   "max-user-labels",
+			Usage:  "Maximum number of distinct users to label, others are labelled \"other\" (0 for unlimited)",
+			EnvVar: "PLEX_MAX_USER_LABELS,MAX_USER_LABELS",
+		},
+		cli.BoolFlag{
+			Name:   "check-plex-releases",
+			Usage:  "Check plex.tv for new Plex Media Server releases",
+			EnvVar: "PLEX_CHECK_RELEASES,CHECK_RELEASES",
+		},
+		cli.DurationFlag{
+			Name:   "library-stats-interval",
+			Usage:  "Interval between crawls of every library item for library size and runtime totals, 0 uses the default of 1h",
+			EnvVar: "PLEX_LIBRARY_STATS_INTERVAL,LIBRARY_STATS_INTERVAL",
+		},
 	}
 
 	app.Commands = []cli.Command{
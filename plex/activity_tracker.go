@@ -0,0 +1,49 @@
+package plex
+
+import (
+	"sync"
+
+	"github.com/frebib/plex-exporter/plex/api"
+)
+
+// activityTracker counts background activities by type, remembering the
+// activities seen in the previous poll so each one is only counted once.
+type activityTracker struct {
+	mu     sync.Mutex
+	active map[string]bool
+	counts map[string]float64
+}
+
+func newActivityTracker() *activityTracker {
+	return &activityTracker{
+		active: make(map[string]bool),
+		counts: make(map[string]float64),
+	}
+}
+
+// Update counts any activities not seen in the previous poll
+func (t *activityTracker) Update(activities []api.Activity) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	active := make(map[string]bool, len(activities))
+	for _, a := range activities {
+		active[a.UUID] = true
+		if !t.active[a.UUID] {
+			t.counts[a.Type]++
+		}
+	}
+	t.active = active
+}
+
+// Counts returns the cumulative number of activities seen per type
+func (t *activityTracker) Counts() map[string]float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts := make(map[string]float64, len(t.counts))
+	for k, v := range t.counts {
+		counts[k] = v
+	}
+	return counts
+}
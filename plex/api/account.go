@@ -0,0 +1,19 @@
+package api
+
+type MyPlexAccountResponse struct {
+	MyPlexAccount `json:"MyPlex"`
+}
+
+type MyPlexAccount struct {
+	Username            string  `json:"username"`
+	MappingState        string  `json:"mappingState"`
+	MappingError        string  `json:"mappingError"`
+	MappingErrorMessage string  `json:"mappingErrorMessage"`
+	SignInState         string  `json:"signInState"`
+	PublicAddress       string  `json:"publicAddress"`
+	PublicPort          FlexInt `json:"publicPort"`
+	PrivateAddress      string  `json:"privateAddress"`
+	PrivatePort         FlexInt `json:"privatePort"`
+	SubscriptionActive  bool    `json:"subscriptionActive"`
+	SubscriptionState   string  `json:"subscriptionState"`
+}
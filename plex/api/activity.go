@@ -0,0 +1,20 @@
+package api
+
+type ActivityResponse struct {
+	Activities `json:"MediaContainer"`
+}
+
+type Activities struct {
+	Size       int        `json:"size"`
+	Activities []Activity `json:"Activity"`
+}
+
+type Activity struct {
+	UUID        string `json:"uuid"`
+	Type        string `json:"type"`
+	Cancellable bool   `json:"cancellable"`
+	UserID      int    `json:"userID"`
+	Title       string `json:"title"`
+	Subtitle    string `json:"subtitle"`
+	Progress    int    `json:"progress"`
+}
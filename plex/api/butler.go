@@ -0,0 +1,18 @@
+package api
+
+type ButlerResponse struct {
+	ButlerTasks `json:"ButlerTasks"`
+}
+
+type ButlerTasks struct {
+	Tasks []ButlerTask `json:"ButlerTask"`
+}
+
+type ButlerTask struct {
+	Name               string `json:"name"`
+	Title              string `json:"title"`
+	Description        string `json:"description"`
+	Enabled            bool   `json:"enabled"`
+	Interval           int    `json:"interval"`
+	ScheduleRandomized bool   `json:"scheduleRandomized"`
+}
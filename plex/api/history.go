@@ -0,0 +1,50 @@
+package api
+
+type HistoryResponse struct {
+	History `json:"MediaContainer"`
+}
+
+type History struct {
+	Size      int           `json:"size"`
+	TotalSize int           `json:"totalSize"`
+	Items     []HistoryItem `json:"Metadata"`
+}
+
+type HistoryItem struct {
+	HistoryKey string `json:"historyKey"`
+	RatingKey  string `json:"ratingKey"`
+	SectionID  string `json:"librarySectionID"`
+	Title      string `json:"title"`
+	Type       string `json:"type"`
+	ViewedAt   int64  `json:"viewedAt"`
+	AccountID  int    `json:"accountID"`
+	DeviceID   int    `json:"deviceID"`
+}
+
+type AccountListResponse struct {
+	Accounts `json:"MediaContainer"`
+}
+
+type Accounts struct {
+	Accounts []Account `json:"Account"`
+}
+
+type Account struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type ClientDeviceListResponse struct {
+	ClientDevices `json:"MediaContainer"`
+}
+
+type ClientDevices struct {
+	Devices []ClientDevice `json:"Device"`
+}
+
+type ClientDevice struct {
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	Platform         string `json:"platform"`
+	ClientIdentifier string `json:"clientIdentifier"`
+}
@@ -1,5 +1,19 @@
 package api
 
+// Metadata types, used to filter library sections by the type of item
+const (
+	MetadataTypeMovie      = 1
+	MetadataTypeShow       = 2
+	MetadataTypeSeason     = 3
+	MetadataTypeEpisode    = 4
+	MetadataTypeArtist     = 8
+	MetadataTypeAlbum      = 9
+	MetadataTypeTrack      = 10
+	MetadataTypeClip       = 12
+	MetadataTypePhoto      = 13
+	MetadataTypePhotoAlbum = 14
+)
+
 type LibraryResponse struct {
 	Library `json:"MediaContainer"`
 }
@@ -10,9 +24,22 @@ type Library struct {
 }
 
 type Section struct {
-	ID   string `json:"key"`
-	Name string `json:"title"`
-	Type string `json:"type"`
+	ID         string     `json:"key"`
+	Name       string     `json:"title"`
+	Type       string     `json:"type"`
+	UUID       string     `json:"uuid"`
+	Agent      string     `json:"agent"`
+	Scanner    string     `json:"scanner"`
+	Language   string     `json:"language"`
+	Refreshing bool       `json:"refreshing"`
+	ScannedAt  int64      `json:"scannedAt"`
+	UpdatedAt  int64      `json:"updatedAt"`
+	Locations  []Location `json:"Location"`
+}
+
+type Location struct {
+	ID   int    `json:"id"`
+	Path string `json:"path"`
 }
 
 type SectionResponse struct {
@@ -20,5 +47,24 @@ type SectionResponse struct {
 }
 
 type SectionDetail struct {
-	TotalSize int `json:"totalSize"`
+	Size      int        `json:"size"`
+	TotalSize int        `json:"totalSize"`
+	Metadata  []Metadata `json:"Metadata"`
+}
+
+type Metadata struct {
+	RatingKey string   `json:"ratingKey"`
+	GUID      string   `json:"guid"`
+	Type      string   `json:"type"`
+	Title     string   `json:"title"`
+	Thumb     string   `json:"thumb"`
+	Duration  int64    `json:"duration"`
+	AddedAt   int64    `json:"addedAt"`
+	ViewCount int      `json:"viewCount"`
+	Media     []Media  `json:"Media"`
+	Markers   []Marker `json:"Marker"`
+}
+
+type Marker struct {
+	Type string `json:"type"`
 }
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+)
+
+type DVRListResponse struct {
+	DVRs `json:"MediaContainer"`
+}
+
+type DVRs struct {
+	Size int   `json:"size"`
+	DVRs []DVR `json:"Dvr"`
+}
+
+type DVR struct {
+	Key      string      `json:"key"`
+	UUID     string      `json:"uuid"`
+	Lineup   string      `json:"lineup"`
+	Language string      `json:"language"`
+	Country  string      `json:"country"`
+	Devices  []DVRDevice `json:"Device"`
+}
+
+type DVRDevice struct {
+	Key         string  `json:"key"`
+	UUID        string  `json:"uuid"`
+	URI         string  `json:"uri"`
+	Make        string  `json:"make"`
+	Model       string  `json:"model"`
+	ModelNumber string  `json:"modelNumber"`
+	Status      string  `json:"status"`
+	Tuners      FlexInt `json:"tuners"`
+	LastSeenAt  int64   `json:"lastSeenAt"`
+}
+
+type SubscriptionListResponse struct {
+	Subscriptions `json:"MediaContainer"`
+}
+
+type Subscriptions struct {
+	Size          int                 `json:"size"`
+	Subscriptions []MediaSubscription `json:"MediaSubscription"`
+}
+
+type MediaSubscription struct {
+	Key   string `json:"key"`
+	Type  int    `json:"type"`
+	Title string `json:"title"`
+}
+
+type GrabOperationListResponse struct {
+	GrabOperations `json:"MediaContainer"`
+}
+
+type GrabOperations struct {
+	Size       int                  `json:"size"`
+	Operations []MediaGrabOperation `json:"MediaGrabOperation"`
+}
+
+type MediaGrabOperation struct {
+	Key                 string   `json:"key"`
+	MediaSubscriptionID int      `json:"mediaSubscriptionID"`
+	GrabberIdentifier   string   `json:"grabberIdentifier"`
+	DeviceID            string   `json:"deviceID"`
+	Status              string   `json:"status"`
+	Percent             float64  `json:"percent"`
+	Metadata            Metadata `json:"Metadata"`
+}
+
+// FlexInt decodes an integer that Plex may encode as either a JSON number or
+// a string
+type FlexInt int
+
+func (i *FlexInt) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*i = 0
+		return nil
+	}
+	v, err := strconv.Atoi(s)
+	*i = FlexInt(v)
+	return err
+}
@@ -0,0 +1,41 @@
+package api
+
+// Stream types reported by Plex in Stream elements
+const (
+	StreamTypeVideo    = 1
+	StreamTypeAudio    = 2
+	StreamTypeSubtitle = 3
+)
+
+type Media struct {
+	ID              int    `json:"id"`
+	Duration        int64  `json:"duration"`
+	Bitrate         int    `json:"bitrate"`
+	Container       string `json:"container"`
+	VideoResolution string `json:"videoResolution"`
+	VideoCodec      string `json:"videoCodec"`
+	AudioCodec      string `json:"audioCodec"`
+	AudioChannels   int    `json:"audioChannels"`
+	Selected        bool   `json:"selected"`
+	BeginsAt        int64  `json:"beginsAt"`
+	EndsAt          int64  `json:"endsAt"`
+	Parts           []Part `json:"Part"`
+}
+
+type Part struct {
+	ID        int      `json:"id"`
+	Decision  string   `json:"decision"`
+	Container string   `json:"container"`
+	Duration  int64    `json:"duration"`
+	Size      int64    `json:"size"`
+	Streams   []Stream `json:"Stream"`
+}
+
+type Stream struct {
+	StreamType int    `json:"streamType"`
+	Codec      string `json:"codec"`
+	Decision   string `json:"decision"`
+	Location   string `json:"location"`
+	Height     int    `json:"height"`
+	Width      int    `json:"width"`
+}
@@ -0,0 +1,11 @@
+package api
+
+type PlayQueueResponse struct {
+	PlayQueue `json:"MediaContainer"`
+}
+
+type PlayQueue struct {
+	ID         int        `json:"playQueueID"`
+	TotalCount int        `json:"playQueueTotalCount"`
+	Items      []Metadata `json:"Metadata"`
+}
@@ -5,8 +5,36 @@ type ServerInfoResponse struct {
 }
 
 type ServerInfo struct {
-	ID       string `json:"machineIdentifier"`
-	Name     string `json:"friendlyName"`
-	Version  string `json:"version"`
-	Platform string `json:"platform"`
+	ID              string `json:"machineIdentifier"`
+	Name            string `json:"friendlyName"`
+	Version         string `json:"version"`
+	Platform        string `json:"platform"`
+	PlatformVersion string `json:"platformVersion"`
+
+	MyPlexSubscription            bool   `json:"myPlexSubscription"`
+	AllowSync                     bool   `json:"allowSync"`
+	AllowCameraUpload             bool   `json:"allowCameraUpload"`
+	AllowSharing                  bool   `json:"allowSharing"`
+	OwnerFeatures                 string `json:"ownerFeatures"`
+	TranscoderActiveVideoSessions int    `json:"transcoderActiveVideoSessions"`
+	TranscoderVideo               bool   `json:"transcoderVideo"`
+	TranscoderAudio               bool   `json:"transcoderAudio"`
+	TranscoderPhoto               bool   `json:"transcoderPhoto"`
+	TranscoderSubtitles           bool   `json:"transcoderSubtitles"`
+	TranscoderLyrics              bool   `json:"transcoderLyrics"`
+
+	MediaProviders []MediaProvider `json:"MediaProvider"`
+}
+
+type MediaProvider struct {
+	Identifier string    `json:"identifier"`
+	Title      string    `json:"title"`
+	Types      string    `json:"types"`
+	Protocols  string    `json:"protocols"`
+	Features   []Feature `json:"Feature"`
+}
+
+type Feature struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
 }
@@ -10,11 +10,26 @@ type Sessions struct {
 }
 
 type SessionMetadata struct {
-	Session `json:"Session"`
-	Player  `json:"Player"`
+	SessionKey       string            `json:"sessionKey"`
+	RatingKey        string            `json:"ratingKey"`
+	SectionID        string            `json:"librarySectionID"`
+	SectionTitle     string            `json:"librarySectionTitle"`
+	Title            string            `json:"title"`
+	Type             string            `json:"type"`
+	User             User              `json:"User"`
+	Media            []Media           `json:"Media"`
+	TranscodeSession *TranscodeSession `json:"TranscodeSession"`
+	Session          `json:"Session"`
+	Player           `json:"Player"`
+}
+
+type User struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
 }
 
 type Session struct {
+	ID        string `json:"id"`
 	Bandwidth int    `json:"bandwidth"`
 	Location  string `json:"location"`
 }
@@ -0,0 +1,60 @@
+package api
+
+type ResourceStatisticsResponse struct {
+	ResourceStatistics `json:"MediaContainer"`
+}
+
+type ResourceStatistics struct {
+	Size      int                 `json:"size"`
+	Resources []ResourceStatistic `json:"StatisticsResources"`
+}
+
+type ResourceStatistic struct {
+	Timespan                 int     `json:"timespan"`
+	At                       int64   `json:"at"`
+	HostCPUUtilization       float64 `json:"hostCpuUtilization"`
+	ProcessCPUUtilization    float64 `json:"processCpuUtilization"`
+	HostMemoryUtilization    float64 `json:"hostMemoryUtilization"`
+	ProcessMemoryUtilization float64 `json:"processMemoryUtilization"`
+}
+
+type BandwidthStatisticsResponse struct {
+	BandwidthStatistics `json:"MediaContainer"`
+}
+
+type BandwidthStatistics struct {
+	Size      int                  `json:"size"`
+	Devices   []ClientDevice       `json:"Device"`
+	Accounts  []Account            `json:"Account"`
+	Bandwidth []BandwidthStatistic `json:"StatisticsBandwidth"`
+}
+
+type BandwidthStatistic struct {
+	AccountID int   `json:"accountID"`
+	DeviceID  int   `json:"deviceID"`
+	Timespan  int   `json:"timespan"`
+	At        int64 `json:"at"`
+	LAN       bool  `json:"lan"`
+	Bytes     int64 `json:"bytes"`
+}
+
+type MediaStatisticsResponse struct {
+	MediaStatistics `json:"MediaContainer"`
+}
+
+type MediaStatistics struct {
+	Size     int              `json:"size"`
+	Devices  []ClientDevice   `json:"Device"`
+	Accounts []Account        `json:"Account"`
+	Media    []MediaStatistic `json:"StatisticsMedia"`
+}
+
+type MediaStatistic struct {
+	AccountID    int   `json:"accountID"`
+	DeviceID     int   `json:"deviceID"`
+	Timespan     int   `json:"timespan"`
+	At           int64 `json:"at"`
+	MetadataType int   `json:"metadataType"`
+	Count        int   `json:"count"`
+	Duration     int64 `json:"duration"`
+}
@@ -0,0 +1,34 @@
+package api
+
+type TranscodeSessionList struct {
+	TranscodeSessions `json:"MediaContainer"`
+}
+
+type TranscodeSessions struct {
+	Size     int                `json:"size"`
+	Sessions []TranscodeSession `json:"TranscodeSession"`
+}
+
+type TranscodeSession struct {
+	Key              string  `json:"key"`
+	Throttled        bool    `json:"throttled"`
+	Complete         bool    `json:"complete"`
+	Progress         float64 `json:"progress"`
+	Speed            float64 `json:"speed"`
+	Context          string  `json:"context"`
+	VideoDecision    string  `json:"videoDecision"`
+	AudioDecision    string  `json:"audioDecision"`
+	SubtitleDecision string  `json:"subtitleDecision"`
+	Protocol         string  `json:"protocol"`
+	Container        string  `json:"container"`
+	VideoCodec       string  `json:"videoCodec"`
+	AudioCodec       string  `json:"audioCodec"`
+	Width            int     `json:"width"`
+	Height           int     `json:"height"`
+	SourceVideoCodec string  `json:"sourceVideoCodec"`
+	SourceAudioCodec string  `json:"sourceAudioCodec"`
+	HwRequested      bool    `json:"transcodeHwRequested"`
+	HwDecoding       string  `json:"transcodeHwDecoding"`
+	HwEncoding       string  `json:"transcodeHwEncoding"`
+	HwFullPipeline   bool    `json:"transcodeHwFullPipeline"`
+}
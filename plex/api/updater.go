@@ -0,0 +1,32 @@
+package api
+
+type UpdaterStatusResponse struct {
+	UpdaterStatus `json:"MediaContainer"`
+}
+
+type UpdaterStatus struct {
+	CanInstall  bool      `json:"canInstall"`
+	CheckedAt   int64     `json:"checkedAt"`
+	DownloadURL string    `json:"downloadURL"`
+	Releases    []Release `json:"Release"`
+}
+
+type Release struct {
+	Key         string `json:"key"`
+	Version     string `json:"version"`
+	State       string `json:"state"`
+	DownloadURL string `json:"downloadURL"`
+}
+
+// Downloads is the list of Plex Media Server releases published on plex.tv,
+// keyed by category then platform.
+type Downloads struct {
+	Computer map[string]DownloadPlatform `json:"computer"`
+	NAS      map[string]DownloadPlatform `json:"nas"`
+}
+
+type DownloadPlatform struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
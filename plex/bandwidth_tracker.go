@@ -0,0 +1,87 @@
+package plex
+
+import (
+	"sync"
+
+	"github.com/frebib/plex-exporter/plex/api"
+)
+
+// BandwidthLabels identifies the counter a bandwidth bucket is accounted to
+type BandwidthLabels struct {
+	Device   string
+	Platform string
+	Account  string
+	Location string
+}
+
+// bandwidthTracker accumulates the bandwidth history buckets reported by the
+// server, counting the bytes added to each bucket since the last poll.
+type bandwidthTracker struct {
+	mu      sync.Mutex
+	buckets *bucketDeltas[api.BandwidthStatistic, api.BandwidthStatistic]
+	bytes   map[BandwidthLabels]float64
+}
+
+func newBandwidthTracker() *bandwidthTracker {
+	key := func(b api.BandwidthStatistic) api.BandwidthStatistic {
+		b.Bytes = 0
+		return b
+	}
+	at := func(b api.BandwidthStatistic) int64 { return b.At }
+	return &bandwidthTracker{
+		buckets: newBucketDeltas(key, at),
+		bytes:   make(map[BandwidthLabels]float64),
+	}
+}
+
+// Update ingests the bytes added to each bucket since the last poll.
+func (t *bandwidthTracker) Update(stats *api.BandwidthStatistics, account func(string) string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	devices := make(map[int]api.ClientDevice, len(stats.Devices))
+	for _, d := range stats.Devices {
+		devices[d.ID] = d
+	}
+	accounts := make(map[int]string, len(stats.Accounts))
+	for _, a := range stats.Accounts {
+		accounts[a.ID] = a.Name
+	}
+
+	t.buckets.update(stats.Bandwidth, func(b api.BandwidthStatistic, previous *api.BandwidthStatistic) {
+		bytes := b.Bytes
+		if previous != nil {
+			bytes -= previous.Bytes
+		}
+		if bytes <= 0 {
+			return
+		}
+
+		location := "wan"
+		if b.LAN {
+			location = "lan"
+		}
+		device := devices[b.DeviceID]
+		t.bytes[BandwidthLabels{
+			Device:   device.Name,
+			Platform: device.Platform,
+			Account:  account(accounts[b.AccountID]),
+			Location: location,
+		}] += float64(bytes)
+	})
+}
+
+// Bandwidth returns the cumulative bytes transferred
+func (t *bandwidthTracker) Bandwidth() []BandwidthMetric {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	metrics := make([]BandwidthMetric, 0, len(t.bytes))
+	for l, bytes := range t.bytes {
+		metrics = append(metrics, BandwidthMetric{
+			BandwidthLabels: l,
+			Bytes:           bytes,
+		})
+	}
+	return metrics
+}
@@ -1,41 +1,104 @@
 package plex
 
 import (
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/frebib/plex-exporter/config"
+	"github.com/frebib/plex-exporter/plex/api"
 	log "github.com/sirupsen/logrus"
 )
 
 type PlexClient struct {
-	Logger *log.Entry
-	server *Server
+	Logger     *log.Entry
+	server     *Server
+	users      *userLabeler
+	sessions   *sessionTracker
+	bandwidth  *bandwidthTracker
+	activities *activityTracker
+	releases   ReleaseChecker
+
+	libraryStats *libraryStatsCache
+	freshness    *freshnessTracker
+	history      *historyTracker
+	mediaStats   *mediaStatsTracker
+	names        *nameResolver
 }
 
-func NewPlexClient(s *Server, l *log.Entry) (*PlexClient, error) {
+func NewPlexClient(s *Server, conf *config.PlexConfig, l *log.Entry) (*PlexClient, error) {
+	var users *userLabeler
+	if conf.UserLabels {
+		if conf.HashUserLabels && conf.UserLabelKey == "" {
+			l.Warn("No user label key is set, hashed user labels will change on restart")
+		}
+		var err error
+		users, err = newUserLabeler(conf.HashUserLabels, conf.UserLabelKey, conf.MaxUserLabels)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var releases ReleaseChecker
+	if conf.CheckPlexReleases {
+		releases = newPlexTVReleases()
+	}
+
 	return &PlexClient{
-		Logger: l,
-		server: s,
+		Logger:     l,
+		server:     s,
+		users:      users,
+		sessions:   newSessionTracker(),
+		bandwidth:  newBandwidthTracker(),
+		activities: newActivityTracker(),
+		releases:   releases,
+
+		libraryStats: newLibraryStatsCache(s, conf.LibraryStatsInterval, l),
+		freshness:    newFreshnessTracker(),
+		history:      newHistoryTracker(l),
+		mediaStats:   newMediaStatsTracker(),
+		names:        newNameResolver(s),
 	}, nil
 }
 
+// UserLabels reports whether metrics should be labelled with the user that
+// owns each session.
+func (c *PlexClient) UserLabels() bool {
+	return c.users != nil
+}
+
 // GetServerMetrics fetches all metrics for each server and returns them in a map
 // with the servers' names as keys.
 func (c *PlexClient) GetServerMetrics() (ServerMetric, error) {
-	logger := c.Logger.WithFields(log.Fields{"server": c.server.Name})
+	logger := c.Logger.WithFields(log.Fields{"server": c.server.LastServerInfo().Name})
 
 	var (
 		data   ServerMetric
 		wg     sync.WaitGroup
-		errors = make(chan error)
+		errors = make(chan error, 1)
 	)
 
-	wg.Add(3)
+	// Only the first error is returned. Later errors are dropped rather
+	// than blocking, so every job finishes after an error
+	fail := func(err error) {
+		select {
+		case errors <- err:
+		default:
+		}
+	}
+
+	wg.Add(4)
 
 	call := func(f func() error) {
 		err := f()
 		if err != nil {
-			errors <- err
+			fail(err)
 		}
 		wg.Done()
 	}
@@ -49,6 +112,39 @@ func (c *PlexClient) GetServerMetrics() (ServerMetric, error) {
 		}
 		data.Platform = info.Platform
 		data.Version = info.Version
+		data.PlatformVersion = info.PlatformVersion
+
+		for _, provider := range info.MediaProviders {
+			for _, feature := range provider.Features {
+				data.Features = append(data.Features, FeatureMetric{
+					Provider: provider.Identifier,
+					Feature:  feature.Type,
+				})
+			}
+		}
+
+		// Don't fail the whole scrape without the capabilities, they're
+		// only reported by the root endpoint
+		root, err := c.server.GetServerRoot()
+		if err != nil {
+			logger.WithError(err).Warn("Failed to get server capabilities")
+			return nil
+		}
+		data.PlexPass = root.MyPlexSubscription
+		data.TranscoderActiveVideoSessions = root.TranscoderActiveVideoSessions
+		data.Capabilities = map[string]bool{
+			"sync":                 root.AllowSync,
+			"camera_upload":        root.AllowCameraUpload,
+			"sharing":              root.AllowSharing,
+			"transcoder_video":     root.TranscoderVideo,
+			"transcoder_audio":     root.TranscoderAudio,
+			"transcoder_photo":     root.TranscoderPhoto,
+			"transcoder_subtitles": root.TranscoderSubtitles,
+			"transcoder_lyrics":    root.TranscoderLyrics,
+			// ownerFeatures lists the owner's Plex Pass entitlements, not
+			// whether hardware transcoding is enabled on the server
+			"hardware_transcoding_entitled": slices.Contains(strings.Split(root.OwnerFeatures, ","), "hwtranscode"),
+		}
 		return nil
 	})
 
@@ -61,8 +157,40 @@ func (c *PlexClient) GetServerMetrics() (ServerMetric, error) {
 		}
 		data.ActiveSessions = sessionStatus.Size
 
+		samples := make([]sessionSample, 0, len(sessionStatus.Metadata))
 		for _, metadata := range sessionStatus.Metadata {
+			user := c.users.Label(metadata.User.Title)
+			samples = append(samples, sessionSample{
+				Key:   metadata.SessionKey + "/" + metadata.RatingKey,
+				State: metadata.Player.State,
+				Labels: PlayLabels{
+					Section:   metadata.SectionTitle,
+					MediaType: metadata.Type,
+					User:      user,
+				},
+			})
+
+			source, target := sessionResolutions(metadata)
+			data.Sessions = append(data.Sessions, SessionMetric{
+				ID:               metadata.Session.ID,
+				Device:           metadata.Player.Device,
+				Platform:         metadata.Player.Platform,
+				Location:         metadata.Session.Location,
+				Bandwidth:        metadata.Session.Bandwidth,
+				Decision:         sessionDecision(metadata),
+				MediaType:        metadata.Type,
+				SourceResolution: source,
+				TargetResolution: target,
+			})
+			switch metadata.Session.Location {
+			case "lan":
+				data.LANBandwidth += metadata.Session.Bandwidth
+			case "wan":
+				data.WANBandwidth += metadata.Session.Bandwidth
+			}
+
 			data.Players = append(data.Players, PlayerMetric{
+				User:     user,
 				Device:   metadata.Player.Device,
 				Platform: metadata.Player.Platform,
 				Profile:  metadata.Player.Profile,
@@ -72,6 +200,10 @@ func (c *PlexClient) GetServerMetrics() (ServerMetric, error) {
 				Secure:   strconv.FormatBool(metadata.Player.Secure),
 			})
 		}
+
+		c.sessions.Update(samples)
+		data.Plays = c.sessions.Plays()
+		data.States, data.BufferingCount = c.sessions.States()
 		return nil
 	})
 
@@ -93,25 +225,90 @@ func (c *PlexClient) GetServerMetrics() (ServerMetric, error) {
 				id, err := strconv.Atoi(section.ID)
 				if err != nil {
 					logger.WithError(err).Debugf("Could not convert sections ID to int. (%s)", section.ID)
-					errors <- err
+					fail(err)
 					return
 				}
-				size, err := c.server.GetSectionSize(id)
+				size, err := c.server.GetSectionSize(id, nil)
 				if err != nil {
 					logger.WithError(err).Debugf("Could not get section size for \"%s\"", section.Name)
-					errors <- err
+					fail(err)
 					return
 				}
+
+				// Item counts are left out if they can't be fetched, rather
+				// than failing the whole scrape
+				items := make(map[string]int)
+				for _, t := range sectionItemTypes[section.Type] {
+					filters := url.Values{"type": {strconv.Itoa(t.Type)}}
+					count, err := c.server.GetSectionSize(id, filters)
+					if err != nil {
+						logger.WithError(err).Warnf("Could not get %s count for \"%s\"", t.Name, section.Name)
+						continue
+					}
+					items[t.Name] = count
+				}
+
+				var collections *int
+				if count, err := c.server.GetCollectionCount(id); err != nil {
+					logger.WithError(err).Warnf("Could not get collection count for \"%s\"", section.Name)
+				} else {
+					collections = &count
+				}
+
+				// A newest item of 0 is left out of the metrics
+				newest, err := c.getSectionNewest(id, section)
+				if err != nil {
+					logger.WithError(err).Warnf("Could not get newest item for \"%s\"", section.Name)
+				}
+
 				data.Libraries[i] = LibraryMetric{
-					Name: section.Name,
-					Type: section.Type,
-					Size: size,
+					Name:        section.Name,
+					Type:        section.Type,
+					Size:        size,
+					Collections: collections,
+					Items:       items,
+					NewestItem:  newest,
+					ItemsAdded:  c.freshness.Added(section.ID),
+
+					UUID:       section.UUID,
+					Agent:      section.Agent,
+					Scanner:    section.Scanner,
+					Language:   section.Language,
+					Refreshing: section.Refreshing,
+					ScannedAt:  section.ScannedAt,
+				}
+				for _, location := range section.Locations {
+					data.Libraries[i].Locations = append(data.Libraries[i].Locations, location.Path)
+				}
+				if stats, ok := c.libraryStats.Get(section.ID); ok {
+					data.Libraries[i].Stats = &stats
 				}
 			}(i)
 		}
 		return nil
 	})
 
+	// Get playlist metrics
+	go call(func() error {
+		for _, playlistType := range []string{"video", "audio", "photo"} {
+			for _, smart := range []bool{false, true} {
+				// Playlist counts are left out if they can't be fetched,
+				// rather than failing the whole scrape
+				count, err := c.server.GetPlaylistCount(playlistType, smart)
+				if err != nil {
+					logger.WithError(err).Warnf("Could not get %s playlist count", playlistType)
+					continue
+				}
+				data.Playlists = append(data.Playlists, PlaylistMetric{
+					Type:  playlistType,
+					Smart: smart,
+					Count: count,
+				})
+			}
+		}
+		return nil
+	})
+
 	go func() {
 		wg.Wait()
 
@@ -122,6 +319,603 @@ func (c *PlexClient) GetServerMetrics() (ServerMetric, error) {
 		}
 	}()
 
-	// Wait for an error (or nil), then return
-	return data, <-errors
+	// Wait for an error (or nil), then return. Jobs may still be running
+	// after an error, so data can't be read safely
+	if err := <-errors; err != nil {
+		return ServerMetric{}, err
+	}
+	return data, nil
+}
+
+// getSectionNewest returns the addedAt timestamp of the newest item in a
+// library section, counting any items added since the last poll.
+func (c *PlexClient) getSectionNewest(id int, section api.Section) (int64, error) {
+	leafTypes := sectionLeafTypes(section.Type)
+	if len(leafTypes) == 0 {
+		return 0, nil
+	}
+	leafType := strconv.Itoa(leafTypes[0])
+	filters := url.Values{"type": {leafType}, "sort": {"addedAt:desc"}}
+	resp, err := c.server.GetSectionItems(id, filters, 0, 1)
+	if err != nil || len(resp.Metadata) == 0 {
+		return 0, err
+	}
+	newest := resp.Metadata[0].AddedAt
+
+	previous, seen := c.freshness.Newest(section.ID)
+	added := 0
+	if seen && newest > previous {
+		// The ">>" suffix filters for values strictly greater than
+		filters := url.Values{"type": {leafType}, "addedAt>>": {strconv.FormatInt(previous, 10)}}
+		added, err = c.server.GetSectionSize(id, filters)
+		if err != nil {
+			return 0, err
+		}
+	}
+	c.freshness.Record(section.ID, previous, newest, added)
+	return newest, nil
+}
+
+type itemType struct {
+	Name string
+	Type int
+	// Leaf is set for types at the bottom of the hierarchy, which hold the
+	// media files
+	Leaf bool
+}
+
+// sectionItemTypes lists the types of item counted in each type of library
+// section, from the top of the hierarchy down
+var sectionItemTypes = map[string][]itemType{
+	"movie":  {{"movie", api.MetadataTypeMovie, true}},
+	"show":   {{"show", api.MetadataTypeShow, false}, {"season", api.MetadataTypeSeason, false}, {"episode", api.MetadataTypeEpisode, true}},
+	"artist": {{"artist", api.MetadataTypeArtist, false}, {"album", api.MetadataTypeAlbum, false}, {"track", api.MetadataTypeTrack, true}},
+	"photo":  {{"photoalbum", api.MetadataTypePhotoAlbum, false}, {"photo", api.MetadataTypePhoto, true}, {"clip", api.MetadataTypeClip, true}},
+}
+
+// sectionLeafTypes returns the leaf item types of a type of library section
+func sectionLeafTypes(sectionType string) []int {
+	var types []int
+	for _, t := range sectionItemTypes[sectionType] {
+		if t.Leaf {
+			types = append(types, t.Type)
+		}
+	}
+	return types
+}
+
+// Play decisions reported for sessions
+const (
+	DecisionDirectPlay   = "directplay"
+	DecisionDirectStream = "directstream"
+	DecisionTranscode    = "transcode"
+)
+
+// selectedMedia returns the media version being played by a session.
+func selectedMedia(m api.SessionMetadata) *api.Media {
+	for i := range m.Media {
+		if m.Media[i].Selected {
+			return &m.Media[i]
+		}
+	}
+	if len(m.Media) > 0 {
+		return &m.Media[0]
+	}
+	return nil
+}
+
+// sessionDecision classifies a session as direct play, direct stream (the
+// container is remuxed but streams are copied) or transcode.
+func sessionDecision(m api.SessionMetadata) string {
+	t := m.TranscodeSession
+	if t == nil {
+		return DecisionDirectPlay
+	}
+	if t.VideoDecision == "transcode" || t.AudioDecision == "transcode" {
+		return DecisionTranscode
+	}
+
+	if media := selectedMedia(m); media != nil {
+		for _, part := range media.Parts {
+			for _, stream := range part.Streams {
+				if stream.Decision == "transcode" {
+					return DecisionTranscode
+				}
+			}
+			if part.Decision == DecisionDirectPlay {
+				return DecisionDirectPlay
+			}
+		}
+	}
+	return DecisionDirectStream
+}
+
+// sessionResolutions returns the source video resolution of a session, and
+// the resolution it is delivered to the client at.
+func sessionResolutions(m api.SessionMetadata) (source, target string) {
+	if media := selectedMedia(m); media != nil {
+		source = media.VideoResolution
+	}
+
+	target = source
+	if t := m.TranscodeSession; t != nil && t.VideoDecision == "transcode" && t.Height > 0 {
+		target = resolutionFromHeight(t.Height)
+	}
+	return source, target
+}
+
+// resolutionFromHeight maps a video height to the resolution names used by
+// Plex for videoResolution.
+func resolutionFromHeight(height int) string {
+	switch {
+	case height > 1440:
+		return "4k"
+	case height > 720:
+		return "1080"
+	case height > 576:
+		return "720"
+	case height > 480:
+		return "576"
+	case height > 360:
+		return "480"
+	default:
+		return "sd"
+	}
+}
+
+// GetTranscodeMetrics fetches the transcode sessions currently running on the
+// server.
+func (c *PlexClient) GetTranscodeMetrics() ([]TranscodeMetric, error) {
+	transcodes, err := c.server.GetTranscodeSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make([]TranscodeMetric, 0, len(transcodes.Sessions))
+	for _, t := range transcodes.Sessions {
+		metrics = append(metrics, TranscodeMetric{
+			Key:           path.Base(t.Key),
+			VideoDecision: t.VideoDecision,
+			AudioDecision: t.AudioDecision,
+			HwDecoding:    t.HwDecoding,
+			HwEncoding:    t.HwEncoding,
+			Container:     t.Container,
+			Speed:         t.Speed,
+			Throttled:     t.Throttled,
+		})
+	}
+	return metrics, nil
+}
+
+// metadataTypeNames names the metadata types reported in playback statistics
+var metadataTypeNames = map[int]string{
+	api.MetadataTypeMovie:   "movie",
+	api.MetadataTypeEpisode: "episode",
+	api.MetadataTypeTrack:   "track",
+	api.MetadataTypeClip:    "clip",
+	api.MetadataTypePhoto:   "photo",
+}
+
+// GetMediaStatsMetrics ingests any new playback statistics from the server
+// and returns the cumulative plays and watch time per account, device and
+// media type.
+func (c *PlexClient) GetMediaStatsMetrics() ([]MediaStatsMetric, error) {
+	stats, err := c.server.GetMediaStatistics()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mediaStats.Update(stats.Media, func(s api.MediaStatistic) MediaStatsLabels {
+		device := c.names.Device(s.DeviceID)
+		labels := MediaStatsLabels{
+			Device:    device.Name,
+			Platform:  device.Platform,
+			MediaType: metadataTypeNames[s.MetadataType],
+		}
+		if labels.MediaType == "" {
+			labels.MediaType = strconv.Itoa(s.MetadataType)
+		}
+		if c.UserLabels() {
+			labels.Account = c.users.Label(c.names.Account(s.AccountID))
+		}
+		return labels
+	})
+	return c.mediaStats.Totals(), nil
+}
+
+// GetConversionMetrics fetches the transcoder's conversion queue, used for
+// optimized versions and downloads, along with the conversions in progress.
+func (c *PlexClient) GetConversionMetrics() (ConversionMetric, error) {
+	var data ConversionMetric
+
+	queue, err := c.server.GetConversionQueue()
+	if err != nil {
+		return data, err
+	}
+	data.Queued = queue.TotalCount
+	for _, item := range queue.Items {
+		if len(item.Media) == 0 {
+			continue
+		}
+		for _, part := range item.Media[0].Parts {
+			data.QueueBytes += part.Size
+		}
+	}
+
+	// Conversions are transcoded in the "static" context, rather than
+	// "streaming" for playback
+	transcodes, err := c.server.GetTranscodeSessions()
+	if err != nil {
+		return data, err
+	}
+	for _, t := range transcodes.Sessions {
+		if t.Context == "static" {
+			data.Processing++
+		}
+	}
+	return data, nil
+}
+
+// GetResourceMetrics fetches the most recent host and Plex process resource
+// utilisation sample recorded by the server.
+func (c *PlexClient) GetResourceMetrics() (ResourceMetric, error) {
+	stats, err := c.server.GetResourceStatistics()
+	if err != nil {
+		return ResourceMetric{}, err
+	}
+	if len(stats.Resources) == 0 {
+		return ResourceMetric{}, fmt.Errorf("no resource statistics reported by server")
+	}
+
+	latest := stats.Resources[0]
+	for _, r := range stats.Resources[1:] {
+		if r.At > latest.At {
+			latest = r
+		}
+	}
+
+	return ResourceMetric{
+		HostCPU:       latest.HostCPUUtilization,
+		ProcessCPU:    latest.ProcessCPUUtilization,
+		HostMemory:    latest.HostMemoryUtilization,
+		ProcessMemory: latest.ProcessMemoryUtilization,
+	}, nil
+}
+
+// GetBandwidthMetrics ingests any new bandwidth history from the server and
+// returns the cumulative bytes transferred per device, account and location.
+func (c *PlexClient) GetBandwidthMetrics() ([]BandwidthMetric, error) {
+	stats, err := c.server.GetBandwidthStatistics()
+	if err != nil {
+		return nil, err
+	}
+
+	c.bandwidth.Update(&stats.BandwidthStatistics, c.users.Label)
+	return c.bandwidth.Bandwidth(), nil
+}
+
+// GetActivityMetrics fetches the background activities running on the server,
+// along with the number of activities seen by type since the exporter started.
+func (c *PlexClient) GetActivityMetrics() (ActivityMetric, error) {
+	resp, err := c.server.GetActivities()
+	if err != nil {
+		return ActivityMetric{}, err
+	}
+
+	c.activities.Update(resp.Activities.Activities)
+
+	data := ActivityMetric{Seen: c.activities.Counts()}
+	for _, a := range resp.Activities.Activities {
+		data.Active = append(data.Active, ActivityProgressMetric{
+			Type:     a.Type,
+			Title:    a.Title,
+			Progress: a.Progress,
+		})
+	}
+	return data, nil
+}
+
+// butlerActivityTypes maps Butler tasks to the activity type they report in
+// /activities while running. /butler has no running state of its own, so
+// tasks which don't report an activity can't be seen running.
+var butlerActivityTypes = map[string]string{
+	"BackupDatabase":          "database.backup",
+	"OptimizeDatabase":        "database.optimize",
+	"DeepMediaAnalysis":       "media.analyze.deep",
+	"GenerateChapterThumbs":   "media.generate.chapter.thumbs",
+	"GenerateMediaIndexFiles": "media.generate.bif",
+	"RefreshLibraries":        "library.update.section",
+	"RefreshPeriodicMetadata": "library.refresh.items",
+}
+
+// GetButlerMetrics fetches the scheduled maintenance tasks configured on the
+// server, and whether each is running from the server's activities.
+func (c *PlexClient) GetButlerMetrics() ([]ButlerTaskMetric, error) {
+	resp, err := c.server.GetButlerTasks()
+	if err != nil {
+		return nil, err
+	}
+	activities, err := c.server.GetActivities()
+	if err != nil {
+		return nil, err
+	}
+
+	active := make(map[string]bool)
+	for _, a := range activities.Activities.Activities {
+		active[a.Type] = true
+	}
+
+	metrics := make([]ButlerTaskMetric, 0, len(resp.Tasks))
+	for _, t := range resp.Tasks {
+		activityType, ok := butlerActivityTypes[t.Name]
+		metrics = append(metrics, ButlerTaskMetric{
+			Name:    t.Name,
+			Title:   t.Title,
+			Enabled: t.Enabled,
+			// Butler task intervals are in days
+			Interval: time.Duration(t.Interval) * 24 * time.Hour,
+
+			ReportsActivity: ok,
+			Running:         ok && active[activityType],
+		})
+	}
+	return metrics, nil
+}
+
+// GetUpdateMetrics reports whether a newer release is available for the
+// server, as seen by the server's own updater and optionally plex.tv.
+func (c *PlexClient) GetUpdateMetrics() ([]UpdateMetric, error) {
+	// The server info is refreshed by GetServerMetrics on every scrape
+	info := c.server.LastServerInfo()
+
+	var metrics []UpdateMetric
+
+	status, err := c.server.GetUpdaterStatus()
+	if err != nil {
+		// The updater can be disabled, so only fail without a fallback
+		if c.releases == nil {
+			return nil, err
+		}
+		c.Logger.WithError(err).Debug("Could not get updater status")
+	} else {
+		m := UpdateMetric{Source: "updater", Version: info.Version}
+		if len(status.Releases) > 0 {
+			m.CandidateVersion = status.Releases[0].Version
+			m.Available = compareVersions(m.CandidateVersion, info.Version) > 0
+		}
+		metrics = append(metrics, m)
+	}
+
+	if c.releases != nil {
+		latest, err := c.releases.LatestRelease(info.Platform)
+		if err != nil {
+			// Don't hide the updater status while plex.tv is unavailable
+			if len(metrics) == 0 {
+				return nil, err
+			}
+			c.Logger.WithError(err).Warn("Could not get latest release from plex.tv")
+			return metrics, nil
+		}
+		metrics = append(metrics, UpdateMetric{
+			Source:           "plex.tv",
+			Version:          info.Version,
+			CandidateVersion: latest,
+			Available:        compareVersions(latest, info.Version) > 0,
+		})
+	}
+	return metrics, nil
+}
+
+// compareVersions compares two Plex version strings such as
+// "1.40.1.8227-c0dd5a73d", ignoring the build hash. It returns a positive
+// number if a is newer than b, negative if older and zero if equal.
+func compareVersions(a, b string) int {
+	partsA := strings.Split(strings.SplitN(a, "-", 2)[0], ".")
+	partsB := strings.Split(strings.SplitN(b, "-", 2)[0], ".")
+
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			y, _ = strconv.Atoi(partsB[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+// GetRemoteAccessMetrics fetches the state of the server's remote access port
+// mapping and plex.tv sign in.
+func (c *PlexClient) GetRemoteAccessMetrics() (RemoteAccessMetric, error) {
+	account, err := c.server.GetMyPlexAccount()
+	if err != nil {
+		return RemoteAccessMetric{}, err
+	}
+
+	return RemoteAccessMetric{
+		MappingState:  account.MappingState,
+		MappingError:  account.MappingError,
+		SignInState:   account.SignInState,
+		PublicAddress: account.PublicAddress,
+		PublicPort:    strconv.Itoa(int(account.PublicPort)),
+	}, nil
+}
+
+// GetLibraryHealthMetrics returns the number of items in each library section
+// with metadata issues, from the last background crawl of the library.
+func (c *PlexClient) GetLibraryHealthMetrics() []LibraryHealthMetric {
+	sections := c.libraryStats.Sections()
+
+	metrics := make([]LibraryHealthMetric, 0, len(sections))
+	for _, s := range sections {
+		metrics = append(metrics, LibraryHealthMetric{
+			Name:   s.Name,
+			Type:   s.Type,
+			Issues: s.Health,
+		})
+	}
+	return metrics
+}
+
+// historyPageSize is the number of plays fetched per watch history request
+const historyPageSize = 200
+
+// GetHistoryMetrics ingests any plays added to the server's watch history
+// since the last poll, and returns the cumulative plays per account, device,
+// library section and media type. No plays are returned until the whole
+// history has been ingested in the background.
+func (c *PlexClient) GetHistoryMetrics() ([]HistoryMetric, error) {
+	fetch := func(since int64) ([]api.HistoryItem, error) {
+		// The ">" suffix filters for values greater than or equal to. Paging
+		// oldest first means plays added while paging are appended to the
+		// last page, rather than shifting earlier pages.
+		filters := url.Values{"sort": {"viewedAt:asc"}, "viewedAt>": {strconv.FormatInt(since, 10)}}
+
+		var items []api.HistoryItem
+		seen := make(map[string]bool)
+		for start := 0; ; start += historyPageSize {
+			page, err := c.server.GetHistory(filters, start, historyPageSize)
+			if err != nil {
+				return nil, err
+			}
+			// Plays sharing a timestamp may still move between pages
+			for _, item := range page.Items {
+				if !seen[item.HistoryKey] {
+					seen[item.HistoryKey] = true
+					items = append(items, item)
+				}
+			}
+			if len(page.Items) == 0 || start+len(page.Items) >= page.TotalSize {
+				break
+			}
+		}
+		return items, nil
+	}
+
+	label := func(item api.HistoryItem) HistoryLabels {
+		labels := HistoryLabels{
+			Device:    c.names.Device(item.DeviceID).Name,
+			Section:   c.names.Section(item.SectionID),
+			MediaType: item.Type,
+		}
+		if c.UserLabels() {
+			labels.Account = c.users.Label(c.names.Account(item.AccountID))
+		}
+		return labels
+	}
+
+	if !c.history.Backfilled() {
+		c.history.Backfill(fetch, label)
+		return c.history.Plays(), nil
+	}
+
+	err := c.history.Update(fetch, label)
+	if err != nil {
+		return nil, err
+	}
+	return c.history.Plays(), nil
+}
+
+// GetLiveTVMetrics fetches the DVRs and tuners configured on the server, and
+// the state of scheduled recordings.
+func (c *PlexClient) GetLiveTVMetrics() (LiveTVMetric, error) {
+	var data LiveTVMetric
+
+	dvrs, err := c.server.GetDVRs()
+	if err != nil {
+		return data, err
+	}
+	if len(dvrs.DVRs.DVRs) == 0 {
+		// Recordings can't be scheduled without a DVR
+		return data, nil
+	}
+
+	subscriptions, err := c.server.GetSubscriptions()
+	if err != nil {
+		return data, err
+	}
+	data.RecordingRules = len(subscriptions.Subscriptions.Subscriptions)
+
+	scheduled, err := c.server.GetScheduledRecordings()
+	if err != nil {
+		return data, err
+	}
+	data.Recordings = make(map[string]int)
+	inUse := make(map[string]int)
+	for _, op := range scheduled.Operations {
+		data.Recordings[op.Status]++
+		if op.Status == "inprogress" {
+			inUse[op.DeviceID]++
+		}
+	}
+
+	for _, dvr := range dvrs.DVRs.DVRs {
+		data.DVRs = append(data.DVRs, DVRMetric{
+			Key:      dvr.Key,
+			Lineup:   dvr.Lineup,
+			Language: dvr.Language,
+		})
+		for _, device := range dvr.Devices {
+			data.Tuners += int(device.Tuners)
+
+			// It isn't documented whether grab operations identify their
+			// device by key or by uuid, so match either
+			tunersInUse := inUse[device.UUID]
+			if device.Key != device.UUID {
+				tunersInUse += inUse[device.Key]
+			}
+			data.Devices = append(data.Devices, DVRDeviceMetric{
+				DVR:         dvr.Key,
+				Device:      device.UUID,
+				Make:        device.Make,
+				Model:       device.Model,
+				Up:          device.Status == "alive",
+				Tuners:      int(device.Tuners),
+				TunersInUse: tunersInUse,
+			})
+		}
+	}
+
+	// Without any tuners reported every recording would conflict
+	if data.Tuners > 0 {
+		data.Conflicts = countRecordingConflicts(scheduled.Operations, data.Tuners)
+	}
+	return data, nil
+}
+
+// countRecordingConflicts counts the scheduled recordings that can't be
+// recorded as every tuner is busy with an earlier recording.
+func countRecordingConflicts(operations []api.MediaGrabOperation, tuners int) int {
+	type airing struct{ begins, ends int64 }
+
+	var airings []airing
+	for _, op := range operations {
+		if op.Status != "scheduled" && op.Status != "inprogress" {
+			continue
+		}
+		for _, media := range op.Metadata.Media {
+			if media.BeginsAt > 0 && media.EndsAt > 0 {
+				airings = append(airings, airing{media.BeginsAt, media.EndsAt})
+				break
+			}
+		}
+	}
+	sort.Slice(airings, func(i, j int) bool { return airings[i].begins < airings[j].begins })
+
+	conflicts := 0
+	var recording []int64
+	for _, a := range airings {
+		// Free the tuners of recordings which have finished
+		recording = slices.DeleteFunc(recording, func(ends int64) bool { return ends <= a.begins })
+		if len(recording) >= tuners {
+			conflicts++
+			continue
+		}
+		recording = append(recording, a.ends)
+	}
+	return conflicts
 }
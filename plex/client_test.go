@@ -0,0 +1,63 @@
+package plex
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/frebib/plex-exporter/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// newTestClient creates a client for a fake server, which responds to each
+// path in responses with the JSON body given. The server info is always
+// served, unless overridden.
+func newTestClient(t *testing.T, responses map[string]string) *PlexClient {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	if _, ok := responses["/media/providers"]; !ok {
+		responses["/media/providers"] = `{"MediaContainer":{"machineIdentifier":"abc","friendlyName":"test","version":"1.40.1.8227-c0dd5a73d","platform":"Linux"}}`
+	}
+	for path, body := range responses {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, body)
+		})
+	}
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	server, err := NewServer(config.PlexServerConfig{BaseURL: ts.URL, Token: "token"})
+	if err != nil {
+		t.Fatalf("NewServer: %s", err)
+	}
+	client, err := NewPlexClient(server, &config.PlexConfig{}, log.NewEntry(log.StandardLogger()))
+	if err != nil {
+		t.Fatalf("NewPlexClient: %s", err)
+	}
+	return client
+}
+
+func TestGetRemoteAccessMetrics(t *testing.T) {
+	// A /myplex/account response from Plex Media Server 1.40
+	client := newTestClient(t, map[string]string{
+		"/myplex/account": `{"MyPlex":{"authToken":"token","username":"someone@example.com","mappingState":"mapped","mappingError":"","signInState":"ok","publicAddress":"203.0.113.7","publicPort":32400,"privateAddress":"192.168.1.10","privatePort":32400,"subscriptionFeatures":"hwtranscode,sync","subscriptionActive":true,"subscriptionState":"Active"}}`,
+	})
+
+	got, err := client.GetRemoteAccessMetrics()
+	if err != nil {
+		t.Fatalf("GetRemoteAccessMetrics: %s", err)
+	}
+	want := RemoteAccessMetric{
+		MappingState:  "mapped",
+		MappingError:  "",
+		SignInState:   "ok",
+		PublicAddress: "203.0.113.7",
+		PublicPort:    "32400",
+	}
+	if got != want {
+		t.Errorf("GetRemoteAccessMetrics = %+v, want %+v", got, want)
+	}
+}
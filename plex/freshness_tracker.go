@@ -0,0 +1,65 @@
+package plex
+
+import "sync"
+
+type sectionFreshness struct {
+	newest int64
+	added  float64
+}
+
+// freshnessTracker remembers the newest item seen in each library section,
+// so that items added between polls can be counted.
+type freshnessTracker struct {
+	mu       sync.Mutex
+	sections map[string]*sectionFreshness
+}
+
+func newFreshnessTracker() *freshnessTracker {
+	return &freshnessTracker{
+		sections: make(map[string]*sectionFreshness),
+	}
+}
+
+// Newest returns the addedAt timestamp of the newest item previously seen in
+// a section, and whether the section has been seen before.
+func (t *freshnessTracker) Newest(sectionID string) (int64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.sections[sectionID]
+	if !ok {
+		return 0, false
+	}
+	return s.newest, true
+}
+
+// Record stores the newest item seen in a section, along with the number of
+// items added since the previous newest item. The count is ignored if
+// another poll has already recorded a newer item.
+func (t *freshnessTracker) Record(sectionID string, previous, newest int64, added int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.sections[sectionID]
+	if !ok {
+		t.sections[sectionID] = &sectionFreshness{newest: newest}
+		return
+	}
+	if s.newest != previous || newest <= previous {
+		return
+	}
+	s.newest = newest
+	s.added += float64(added)
+}
+
+// Added returns the number of items added to a section since the exporter
+// started
+func (t *freshnessTracker) Added(sectionID string) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if s, ok := t.sections[sectionID]; ok {
+		return s.added
+	}
+	return 0
+}
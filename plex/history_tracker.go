@@ -0,0 +1,126 @@
+package plex
+
+import (
+	"sync"
+
+	"github.com/frebib/plex-exporter/plex/api"
+	log "github.com/sirupsen/logrus"
+)
+
+// HistoryLabels identifies the counter a play from the watch history is
+// accounted to
+type HistoryLabels struct {
+	Account   string
+	Device    string
+	Section   string
+	MediaType string
+}
+
+// historyTracker ingests the server's watch history incrementally,
+// remembering the time of the last play ingested so each play is only
+// counted once.
+type historyTracker struct {
+	Logger *log.Entry
+
+	// update serialises updates so that concurrent scrapes don't count
+	// plays twice, and guards the last play ingested
+	update   sync.Mutex
+	lastAt   int64
+	lastKeys map[string]bool
+
+	mu          sync.Mutex
+	backfilled  bool
+	backfilling bool
+	plays       map[HistoryLabels]float64
+}
+
+func newHistoryTracker(l *log.Entry) *historyTracker {
+	return &historyTracker{
+		Logger:   l,
+		lastKeys: make(map[string]bool),
+		plays:    make(map[HistoryLabels]float64),
+	}
+}
+
+// Update fetches the plays viewed at or after the last play ingested and
+// counts any not seen before.
+func (t *historyTracker) Update(fetch func(since int64) ([]api.HistoryItem, error), label func(api.HistoryItem) HistoryLabels) error {
+	t.update.Lock()
+	defer t.update.Unlock()
+
+	items, err := fetch(t.lastAt)
+	if err != nil {
+		return err
+	}
+
+	plays := make(map[HistoryLabels]float64)
+	lastAt, lastKeys := t.lastAt, t.lastKeys
+	for _, item := range items {
+		// Plays at the last timestamp may have been ingested already
+		if item.ViewedAt < lastAt || (item.ViewedAt == lastAt && lastKeys[item.HistoryKey]) {
+			continue
+		}
+		plays[label(item)]++
+
+		if item.ViewedAt > t.lastAt {
+			t.lastAt = item.ViewedAt
+			t.lastKeys = make(map[string]bool)
+		}
+		if item.ViewedAt == t.lastAt {
+			t.lastKeys[item.HistoryKey] = true
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for l, n := range plays {
+		t.plays[l] += n
+	}
+	t.backfilled = true
+	return nil
+}
+
+// Backfilled reports whether the whole watch history has been ingested
+func (t *historyTracker) Backfilled() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.backfilled
+}
+
+// Backfill ingests the whole watch history in the background, as paging
+// through it can take far longer than a scrape on large servers. Only one
+// backfill runs at a time, and a failed backfill is retried on the next call.
+func (t *historyTracker) Backfill(fetch func(since int64) ([]api.HistoryItem, error), label func(api.HistoryItem) HistoryLabels) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.backfilling {
+		return
+	}
+	t.backfilling = true
+
+	go func() {
+		err := t.Update(fetch, label)
+		if err != nil {
+			t.Logger.WithError(err).Error("Could not backfill watch history")
+		}
+
+		t.mu.Lock()
+		t.backfilling = false
+		t.mu.Unlock()
+	}()
+}
+
+// Plays returns the cumulative plays ingested from the watch history
+func (t *historyTracker) Plays() []HistoryMetric {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	metrics := make([]HistoryMetric, 0, len(t.plays))
+	for l, plays := range t.plays {
+		metrics = append(metrics, HistoryMetric{
+			HistoryLabels: l,
+			Plays:         plays,
+		})
+	}
+	return metrics
+}
@@ -0,0 +1,350 @@
+package plex
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frebib/plex-exporter/plex/api"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultLibraryStatsInterval is used when no library stats interval is
+// configured
+const DefaultLibraryStatsInterval = time.Hour
+
+// libraryCrawlPageSize is the number of items fetched per request when
+// crawling a library section
+const libraryCrawlPageSize = 200
+
+// qualityFilter is a section filter used to count items by a media quality
+// attribute, one request per value
+type qualityFilter struct {
+	Dimension string
+	Field     string
+	Values    []string
+}
+
+// qualityFilters are counted in sections of video items
+var qualityFilters = []qualityFilter{
+	{"resolution", "resolution", []string{"4k", "1080", "720", "576", "480", "sd"}},
+	{"video_codec", "videoCodec", []string{"h264", "hevc", "av1", "vp9", "mpeg2video", "mpeg4", "vc1"}},
+	{"audio_channels", "audioChannels", []string{"1", "2", "3", "6", "8"}},
+}
+
+// SectionStats are totals computed by crawling every item in a library
+// section
+type SectionStats struct {
+	Name string
+	Type string
+
+	Bytes    int64
+	Duration time.Duration
+	Quality  []QualityCount
+
+	// Watch state is that of the account owning the token
+	ViewCount  int
+	Unwatched  int
+	InProgress int
+	Watched    int
+
+	// Health counts items an admin needs to fix, keyed by issue
+	Health map[string]int
+}
+
+// QualityCount is the number of items in a section with a media quality
+// attribute, such as a resolution of 1080
+type QualityCount struct {
+	Dimension string
+	Value     string
+	Count     int
+}
+
+// Library health issues counted for each item. Plex doesn't report whether
+// marker detection has run, only the markers found, so items without
+// markers include those with no intro or credits to detect.
+const (
+	HealthUnmatched        = "unmatched"
+	HealthMissingArtwork   = "missing_artwork"
+	HealthUnanalyzed       = "unanalyzed"
+	HealthNoIntroMarkers   = "no_intro_markers"
+	HealthNoCreditsMarkers = "no_credits_markers"
+)
+
+// sectionHealthChecks lists the health issues checked for in each type of
+// library section. Photos have no agent match or duration by design, and
+// markers are only detected in video sections.
+var sectionHealthChecks = map[string][]string{
+	"movie":  {HealthUnmatched, HealthMissingArtwork, HealthUnanalyzed, HealthNoCreditsMarkers},
+	"show":   {HealthUnmatched, HealthMissingArtwork, HealthUnanalyzed, HealthNoIntroMarkers, HealthNoCreditsMarkers},
+	"artist": {HealthUnmatched, HealthMissingArtwork, HealthUnanalyzed},
+	"photo":  {HealthMissingArtwork},
+}
+
+// flag counts an item with a health issue, if the issue is checked for in
+// the section
+func (s *SectionStats) flag(issue string) {
+	if _, ok := s.Health[issue]; ok {
+		s.Health[issue]++
+	}
+}
+
+// add accounts a single library item to the section totals
+func (s *SectionStats) add(item api.Metadata) {
+	s.Duration += time.Duration(item.Duration) * time.Millisecond
+	s.ViewCount += item.ViewCount
+	for _, media := range item.Media {
+		for _, part := range media.Parts {
+			s.Bytes += part.Size
+		}
+	}
+
+	// Items without an agent match have a local or "none" agent guid
+	if item.GUID == "" || strings.HasPrefix(item.GUID, "local://") || strings.Contains(item.GUID, "agents.none") {
+		s.flag(HealthUnmatched)
+	}
+	if item.Thumb == "" {
+		s.flag(HealthMissingArtwork)
+	}
+	// Media which hasn't been analysed has no duration or container
+	for _, media := range item.Media {
+		if media.Duration == 0 || media.Container == "" {
+			s.flag(HealthUnanalyzed)
+			break
+		}
+	}
+
+	var intro, credits bool
+	for _, marker := range item.Markers {
+		intro = intro || marker.Type == "intro"
+		credits = credits || marker.Type == "credits"
+	}
+	if !intro {
+		s.flag(HealthNoIntroMarkers)
+	}
+	if !credits {
+		s.flag(HealthNoCreditsMarkers)
+	}
+}
+
+// libraryStatsCache crawls every library section in the background on a slow
+// schedule, as computing totals over every item is too expensive to do on
+// each scrape.
+type libraryStatsCache struct {
+	Logger   *log.Entry
+	server   *Server
+	interval time.Duration
+	start    sync.Once
+
+	mu    sync.RWMutex
+	stats map[string]SectionStats
+}
+
+func newLibraryStatsCache(s *Server, interval time.Duration, l *log.Entry) *libraryStatsCache {
+	if interval <= 0 {
+		interval = DefaultLibraryStatsInterval
+	}
+	return &libraryStatsCache{
+		Logger:   l,
+		server:   s,
+		interval: interval,
+		stats:    make(map[string]SectionStats),
+	}
+}
+
+// Get returns the stats from the last crawl of a library section, starting
+// the background crawl on first use. ok is false until the section has been
+// crawled.
+func (c *libraryStatsCache) Get(sectionID string) (stats SectionStats, ok bool) {
+	c.start.Do(func() { go c.run() })
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	stats, ok = c.stats[sectionID]
+	return stats, ok
+}
+
+// Sections returns the stats from the last crawl of every library section,
+// starting the background crawl on first use.
+func (c *libraryStatsCache) Sections() []SectionStats {
+	c.start.Do(func() { go c.run() })
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sections := make([]SectionStats, 0, len(c.stats))
+	for _, stats := range c.stats {
+		sections = append(sections, stats)
+	}
+	return sections
+}
+
+func (c *libraryStatsCache) run() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.refresh()
+		<-ticker.C
+	}
+}
+
+// refresh crawls every library section, replacing the cached stats for each
+// section crawled successfully. Sections which failed to crawl keep their
+// previous stats, and sections which no longer exist are dropped.
+func (c *libraryStatsCache) refresh() {
+	library, err := c.server.GetLibrary()
+	if err != nil {
+		c.Logger.WithError(err).Error("Could not get library to crawl")
+		return
+	}
+
+	stats := make(map[string]SectionStats, len(library.Sections))
+	for _, section := range library.Sections {
+		start := time.Now()
+		s, err := c.crawlSection(section)
+		if err != nil {
+			c.Logger.WithError(err).Errorf("Could not crawl library section \"%s\"", section.Name)
+
+			c.mu.RLock()
+			previous, ok := c.stats[section.ID]
+			c.mu.RUnlock()
+			if ok {
+				stats[section.ID] = previous
+			}
+			continue
+		}
+		c.Logger.Debugf("Crawled library section \"%s\" in %s", section.Name, time.Since(start))
+		stats[section.ID] = s
+
+		// Make each section available as soon as it has been crawled
+		c.mu.Lock()
+		c.stats[section.ID] = s
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	c.stats = stats
+	c.mu.Unlock()
+}
+
+// crawlSection pages through every item in a library section
+func (c *libraryStatsCache) crawlSection(section api.Section) (SectionStats, error) {
+	stats := SectionStats{
+		Name:   section.Name,
+		Type:   section.Type,
+		Health: make(map[string]int),
+	}
+	for _, issue := range sectionHealthChecks[section.Type] {
+		stats.Health[issue] = 0
+	}
+
+	id, err := strconv.Atoi(section.ID)
+	if err != nil {
+		return stats, err
+	}
+
+	leafTypes := sectionLeafTypes(section.Type)
+	for _, t := range leafTypes {
+		filters := url.Values{"type": {strconv.Itoa(t)}}
+		if section.Type == "movie" || section.Type == "show" {
+			filters.Set("includeMarkers", "1")
+		}
+		for start := 0; ; start += libraryCrawlPageSize {
+			page, err := c.server.GetSectionItems(id, filters, start, libraryCrawlPageSize)
+			if err != nil {
+				return stats, err
+			}
+			for _, item := range page.Metadata {
+				stats.add(item)
+			}
+			if len(page.Metadata) == 0 || start+len(page.Metadata) >= page.TotalSize {
+				break
+			}
+		}
+	}
+
+	// Quality counts are left out if they fail, keeping the crawl totals
+	if section.Type == "movie" || section.Type == "show" {
+		stats.Quality, err = c.countQuality(id, leafTypes[0])
+		if err != nil {
+			c.Logger.WithError(err).Warnf("Could not count media quality in library section \"%s\"", section.Name)
+		}
+	}
+	if section.Type != "photo" && len(leafTypes) > 0 {
+		err = c.countWatchState(id, leafTypes[0], &stats)
+		if err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// countWatchState counts the unwatched, in progress and watched items in a
+// section using section filters, so no items are fetched. Each item is only
+// counted in one state: items in progress are not counted as unwatched or
+// watched, even when being rewatched.
+func (c *libraryStatsCache) countWatchState(id int, itemType int, stats *SectionStats) error {
+	t := strconv.Itoa(itemType)
+	total, err := c.server.GetSectionSize(id, url.Values{"type": {t}})
+	if err != nil {
+		return err
+	}
+	unwatched, err := c.server.GetSectionSize(id, url.Values{"type": {t}, "unwatched": {"1"}})
+	if err != nil {
+		return err
+	}
+	inProgress, err := c.server.GetSectionSize(id, url.Values{"type": {t}, "inProgress": {"1"}})
+	if err != nil {
+		return err
+	}
+	// Items in progress for the first time are also unwatched, the rest
+	// are being rewatched
+	firstWatch, err := c.server.GetSectionSize(id, url.Values{"type": {t}, "unwatched": {"1"}, "inProgress": {"1"}})
+	if err != nil {
+		return err
+	}
+
+	stats.InProgress = inProgress
+	stats.Unwatched = max(unwatched-firstWatch, 0)
+	stats.Watched = max(total-unwatched-(inProgress-firstWatch), 0)
+	return nil
+}
+
+// countQuality counts the video items in a section by resolution, codec, HDR
+// and audio channels using section filters, so no items are fetched. Items
+// not matching any of the values filtered on are counted as "other".
+func (c *libraryStatsCache) countQuality(id int, itemType int) ([]QualityCount, error) {
+	var counts []QualityCount
+
+	total, err := c.server.GetSectionSize(id, url.Values{"type": {strconv.Itoa(itemType)}})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range qualityFilters {
+		sum := 0
+		for _, value := range f.Values {
+			filters := url.Values{"type": {strconv.Itoa(itemType)}, f.Field: {value}}
+			count, err := c.server.GetSectionSize(id, filters)
+			if err != nil {
+				return nil, err
+			}
+			sum += count
+			counts = append(counts, QualityCount{Dimension: f.Dimension, Value: value, Count: count})
+		}
+		counts = append(counts, QualityCount{Dimension: f.Dimension, Value: "other", Count: max(total-sum, 0)})
+	}
+
+	// Plex can only filter on whether an item is HDR, not the HDR format
+	hdr, err := c.server.GetSectionSize(id, url.Values{"type": {strconv.Itoa(itemType)}, "hdr": {"1"}})
+	if err != nil {
+		return nil, err
+	}
+	counts = append(counts,
+		QualityCount{Dimension: "hdr", Value: "hdr", Count: hdr},
+		QualityCount{Dimension: "hdr", Value: "sdr", Count: total - hdr},
+	)
+	return counts, nil
+}
@@ -0,0 +1,175 @@
+package plex
+
+import (
+	"testing"
+
+	"github.com/frebib/plex-exporter/plex/api"
+)
+
+// airing creates a grab operation recording from begins to ends
+func airing(status string, begins, ends int64) api.MediaGrabOperation {
+	return api.MediaGrabOperation{
+		Status: status,
+		Metadata: api.Metadata{
+			Media: []api.Media{{BeginsAt: begins, EndsAt: ends}},
+		},
+	}
+}
+
+func TestCountRecordingConflicts(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []api.MediaGrabOperation
+		tuners     int
+		want       int
+	}{
+		{
+			name:       "no recordings",
+			operations: nil,
+			tuners:     2,
+			want:       0,
+		},
+		{
+			name: "overlapping recordings within tuner count",
+			operations: []api.MediaGrabOperation{
+				airing("scheduled", 1000, 1100),
+				airing("scheduled", 1050, 1150),
+			},
+			tuners: 2,
+			want:   0,
+		},
+		{
+			name: "overlapping recordings beyond tuner count",
+			operations: []api.MediaGrabOperation{
+				airing("scheduled", 1000, 1100),
+				airing("scheduled", 1050, 1150),
+				airing("scheduled", 1060, 1120),
+			},
+			tuners: 2,
+			want:   1,
+		},
+		{
+			name: "back to back recordings share a tuner",
+			operations: []api.MediaGrabOperation{
+				airing("scheduled", 1000, 1100),
+				airing("scheduled", 1100, 1200),
+				airing("scheduled", 1200, 1300),
+			},
+			tuners: 1,
+			want:   0,
+		},
+		{
+			name: "recording in progress holds a tuner",
+			operations: []api.MediaGrabOperation{
+				airing("inprogress", 1000, 1100),
+				airing("scheduled", 1050, 1150),
+			},
+			tuners: 1,
+			want:   1,
+		},
+		{
+			name: "finished and failed recordings are ignored",
+			operations: []api.MediaGrabOperation{
+				airing("complete", 1000, 1100),
+				airing("error", 1000, 1100),
+				airing("scheduled", 1050, 1150),
+			},
+			tuners: 1,
+			want:   0,
+		},
+		{
+			name: "recordings without times are ignored",
+			operations: []api.MediaGrabOperation{
+				airing("scheduled", 0, 0),
+				airing("scheduled", 1000, 0),
+				airing("scheduled", 1000, 1100),
+			},
+			tuners: 1,
+			want:   0,
+		},
+		{
+			name: "unsorted recordings",
+			operations: []api.MediaGrabOperation{
+				airing("scheduled", 1200, 1300),
+				airing("scheduled", 1000, 1250),
+				airing("scheduled", 1100, 1150),
+			},
+			tuners: 1,
+			want:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := countRecordingConflicts(tt.operations, tt.tuners)
+			if got != tt.want {
+				t.Errorf("countRecordingConflicts = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLiveTVMetrics(t *testing.T) {
+	const dvrs = `{"MediaContainer":{"size":1,"Dvr":[{"key":"1","lineup":"lineup","language":"eng","Device":[` +
+		`{"key":"10","uuid":"device://tv.plex.grabbers.hdhomerun/AAAA","make":"Silicondust","model":"HDHR5","status":"alive","tuners":"2"},` +
+		`{"key":"11","uuid":"device://tv.plex.grabbers.hdhomerun/BBBB","make":"Silicondust","model":"HDHR5","status":"dead","tuners":2}]}]}}`
+
+	tests := []struct {
+		name      string
+		dvrs      string
+		scheduled string
+		inUse     map[string]int
+		tuners    int
+		conflicts int
+	}{
+		{
+			name: "recordings matched by uuid and key",
+			dvrs: dvrs,
+			scheduled: `{"MediaContainer":{"MediaGrabOperation":[` +
+				`{"status":"inprogress","deviceID":"device://tv.plex.grabbers.hdhomerun/AAAA","Metadata":{"Media":[{"beginsAt":0,"endsAt":100}]}},` +
+				`{"status":"inprogress","deviceID":"11","Metadata":{"Media":[{"beginsAt":0,"endsAt":100}]}},` +
+				`{"status":"scheduled","deviceID":"10","Metadata":{"Media":[{"beginsAt":50,"endsAt":150}]}}]}}`,
+			inUse:     map[string]int{"device://tv.plex.grabbers.hdhomerun/AAAA": 1, "device://tv.plex.grabbers.hdhomerun/BBBB": 1},
+			tuners:    4,
+			conflicts: 0,
+		},
+		{
+			name: "conflicts not counted without tuners",
+			dvrs: `{"MediaContainer":{"size":1,"Dvr":[{"key":"1","Device":[{"key":"10","uuid":"AAAA","status":"alive"}]}]}}`,
+			scheduled: `{"MediaContainer":{"MediaGrabOperation":[` +
+				`{"status":"scheduled","Metadata":{"Media":[{"beginsAt":0,"endsAt":100}]}}]}}`,
+			inUse:     map[string]int{"AAAA": 0},
+			tuners:    0,
+			conflicts: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, map[string]string{
+				"/livetv/dvrs":                   tt.dvrs,
+				"/media/subscriptions":           `{"MediaContainer":{"MediaSubscription":[{"key":"1"}]}}`,
+				"/media/subscriptions/scheduled": tt.scheduled,
+			})
+
+			got, err := client.GetLiveTVMetrics()
+			if err != nil {
+				t.Fatalf("GetLiveTVMetrics: %s", err)
+			}
+			if got.Tuners != tt.tuners {
+				t.Errorf("Tuners = %d, want %d", got.Tuners, tt.tuners)
+			}
+			if got.Conflicts != tt.conflicts {
+				t.Errorf("Conflicts = %d, want %d", got.Conflicts, tt.conflicts)
+			}
+			if len(got.Devices) != len(tt.inUse) {
+				t.Fatalf("got %d devices, want %d", len(got.Devices), len(tt.inUse))
+			}
+			for _, d := range got.Devices {
+				if d.TunersInUse != tt.inUse[d.Device] {
+					t.Errorf("device %s has %d tuners in use, want %d", d.Device, d.TunersInUse, tt.inUse[d.Device])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package plex
+
+import (
+	"sync"
+
+	"github.com/frebib/plex-exporter/plex/api"
+)
+
+// MediaStatsLabels identifies the counters a playback statistics bucket is
+// accounted to
+type MediaStatsLabels struct {
+	Device    string
+	Platform  string
+	Account   string
+	MediaType string
+}
+
+type mediaStatsTotals struct {
+	plays    float64
+	duration float64
+}
+
+// mediaStatsTracker accumulates the playback statistics buckets reported by
+// the server, counting the plays and watch time added to each bucket since
+// the last poll.
+type mediaStatsTracker struct {
+	mu      sync.Mutex
+	buckets *bucketDeltas[api.MediaStatistic, api.MediaStatistic]
+	totals  map[MediaStatsLabels]*mediaStatsTotals
+}
+
+func newMediaStatsTracker() *mediaStatsTracker {
+	key := func(s api.MediaStatistic) api.MediaStatistic {
+		s.Count, s.Duration = 0, 0
+		return s
+	}
+	at := func(s api.MediaStatistic) int64 { return s.At }
+	return &mediaStatsTracker{
+		buckets: newBucketDeltas(key, at),
+		totals:  make(map[MediaStatsLabels]*mediaStatsTotals),
+	}
+}
+
+// Update ingests the plays and watch time added to each bucket since the last
+// poll.
+func (t *mediaStatsTracker) Update(stats []api.MediaStatistic, label func(api.MediaStatistic) MediaStatsLabels) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buckets.update(stats, func(s api.MediaStatistic, previous *api.MediaStatistic) {
+		count, duration := s.Count, s.Duration
+		if previous != nil {
+			count -= previous.Count
+			duration -= previous.Duration
+		}
+		if count <= 0 && duration <= 0 {
+			return
+		}
+
+		l := label(s)
+		totals, ok := t.totals[l]
+		if !ok {
+			totals = &mediaStatsTotals{}
+			t.totals[l] = totals
+		}
+		totals.plays += float64(max(count, 0))
+		totals.duration += float64(max(duration, 0))
+	})
+}
+
+// Totals returns the cumulative plays and watch time
+func (t *mediaStatsTracker) Totals() []MediaStatsMetric {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	metrics := make([]MediaStatsMetric, 0, len(t.totals))
+	for l, totals := range t.totals {
+		metrics = append(metrics, MediaStatsMetric{
+			MediaStatsLabels: l,
+			Plays:            totals.plays,
+			WatchSeconds:     totals.duration,
+		})
+	}
+	return metrics
+}
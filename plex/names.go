@@ -0,0 +1,96 @@
+package plex
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/frebib/plex-exporter/plex/api"
+)
+
+// nameRefreshInterval limits how often the names are refetched when an
+// unknown ID is seen, as IDs of deleted accounts and devices never resolve
+const nameRefreshInterval = time.Minute
+
+// nameResolver resolves the account, device and library section IDs reported
+// in the watch history and statistics to names. Names are fetched from the
+// server when an unknown ID is seen.
+type nameResolver struct {
+	mu     sync.Mutex
+	server *Server
+
+	accounts        map[int]string
+	accountsFetched time.Time
+	devices         map[int]api.ClientDevice
+	devicesFetched  time.Time
+	sections        map[string]string
+	sectionsFetched time.Time
+}
+
+func newNameResolver(s *Server) *nameResolver {
+	return &nameResolver{
+		server:   s,
+		accounts: make(map[int]string),
+		devices:  make(map[int]api.ClientDevice),
+		sections: make(map[string]string),
+	}
+}
+
+// Account returns the name of an account, or its ID if it can't be found
+func (r *nameResolver) Account(id int) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.accounts[id]; !ok && time.Since(r.accountsFetched) > nameRefreshInterval {
+		r.accountsFetched = time.Now()
+		if resp, err := r.server.GetAccounts(); err == nil {
+			for _, a := range resp.Accounts.Accounts {
+				r.accounts[a.ID] = a.Name
+			}
+		}
+	}
+	if name, ok := r.accounts[id]; ok {
+		return name
+	}
+	return strconv.Itoa(id)
+}
+
+// Device returns a client device, with its ID as the name if it can't be
+// found
+func (r *nameResolver) Device(id int) api.ClientDevice {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.devices[id]; !ok && time.Since(r.devicesFetched) > nameRefreshInterval {
+		r.devicesFetched = time.Now()
+		if resp, err := r.server.GetDevices(); err == nil {
+			for _, d := range resp.Devices {
+				r.devices[d.ID] = d
+			}
+		}
+	}
+	if device, ok := r.devices[id]; ok {
+		return device
+	}
+	return api.ClientDevice{ID: id, Name: strconv.Itoa(id)}
+}
+
+// Section returns the name of a library section, or its ID if it can't be
+// found
+func (r *nameResolver) Section(id string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sections[id]; !ok && time.Since(r.sectionsFetched) > nameRefreshInterval {
+		r.sectionsFetched = time.Now()
+		if resp, err := r.server.GetLibrary(); err == nil {
+			for _, s := range resp.Sections {
+				r.sections[s.ID] = s.Name
+			}
+		}
+	}
+	if name, ok := r.sections[id]; ok {
+		return name
+	}
+	return id
+}
@@ -6,6 +6,7 @@ import (
 	"maps"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/frebib/plex-exporter/config"
@@ -14,6 +15,14 @@ import (
 
 var ErrPinNotAuthorised = errors.New("pin not authorised")
 
+const DownloadsURI = "https://plex.tv/api/downloads/5.json"
+
+// ReleaseChecker finds the latest public release of Plex Media Server for a
+// server platform.
+type ReleaseChecker interface {
+	LatestRelease(platform string) (string, error)
+}
+
 type PinRequest struct {
 	Pin `json:"pin"`
 }
@@ -89,3 +98,53 @@ func GetTokenFromPinRequest(p *PinRequest) (string, error) {
 	}
 	return resp.AuthToken, nil
 }
+
+// plexTVPlatforms maps the platforms reported by servers to the platform
+// names used by the plex.tv downloads API, where they differ
+var plexTVPlatforms = map[string]string{
+	"MacOSX": "MacOS",
+}
+
+// plexTVReleases looks up public releases from the plex.tv downloads API. The
+// response is cached to avoid querying plex.tv on every scrape.
+type plexTVReleases struct {
+	mu         sync.Mutex
+	httpClient *http.Client
+	url        string
+	ttl        time.Duration
+	fetched    time.Time
+	downloads  *api.Downloads
+}
+
+func newPlexTVReleases() *plexTVReleases {
+	return &plexTVReleases{
+		httpClient: &http.Client{Timeout: time.Second * 10},
+		url:        DownloadsURI,
+		ttl:        time.Hour,
+	}
+}
+
+// LatestRelease returns the latest public release version for the platform
+// reported by a server, such as "Linux" or "Windows".
+func (r *plexTVReleases) LatestRelease(platform string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.downloads == nil || time.Since(r.fetched) > r.ttl {
+		downloads, err := httpRequest[api.Downloads](r.httpClient, http.MethodGet, r.url, DefaultHeaders)
+		if err != nil {
+			return "", fmt.Errorf("could not fetch releases from plex.tv: %w", err)
+		}
+		r.downloads = downloads
+		r.fetched = time.Now()
+	}
+
+	if p, ok := plexTVPlatforms[platform]; ok {
+		platform = p
+	}
+	release, ok := r.downloads.Computer[platform]
+	if !ok {
+		return "", fmt.Errorf("no release found for platform %s", platform)
+	}
+	return release.Version, nil
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"maps"
 	"net/http"
+	"net/url"
 	"runtime"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/frebib/plex-exporter/config"
@@ -20,13 +23,39 @@ type Server struct {
 	token      string
 	httpClient *http.Client
 	headers    map[string]string
+
+	// mu guards the last-known server info, which is refreshed by scrapes
+	// running in parallel
+	mu   sync.Mutex
+	info api.ServerInfo
 }
 
 const TestURI = "%s/identity"
+const RootURI = "%s/"
 const ServerInfoURI = "%s/media/providers"
 const StatusURI = "%s/status/sessions"
+const HistoryURI = "%s/status/sessions/history/all"
+const AccountsURI = "%s/accounts"
+const DevicesURI = "%s/devices"
+const TranscodeURI = "%s/transcode/sessions"
+
+// ConversionQueueURI is the play queue used by the transcoder for optimized
+// versions and downloads
+const ConversionQueueURI = "%s/playQueues/1"
+const ResourcesURI = "%s/statistics/resources?timespan=6"
+const BandwidthURI = "%s/statistics/bandwidth?timespan=6"
+const MediaStatisticsURI = "%s/statistics/media?timespan=4"
+const ActivitiesURI = "%s/activities"
+const ButlerURI = "%s/butler"
+const UpdaterURI = "%s/updater/status"
+const MyPlexAccountURI = "%s/myplex/account"
+const DVRsURI = "%s/livetv/dvrs"
+const SubscriptionsURI = "%s/media/subscriptions"
+const ScheduledURI = "%s/media/subscriptions/scheduled"
 const LibraryURI = "%s/library/sections"
 const SectionURI = "%s/library/sections/%d/all"
+const CollectionsURI = "%s/library/sections/%d/collections"
+const PlaylistsURI = "%s/playlists"
 
 var DefaultHeaders = map[string]string{
 	"User-Agent":               fmt.Sprintf("plex_exporter/%s", version.Version),
@@ -66,30 +95,155 @@ func (s *Server) GetServerInfo() (*api.ServerInfoResponse, error) {
 		return nil, err
 	}
 	// Cache last-known ID (shouldn't ever change) and name
+	s.mu.Lock()
 	s.ID = info.ID
 	s.Name = info.Name
+	s.info = info.ServerInfo
+	s.mu.Unlock()
 	return info, nil
 }
 
+// LastServerInfo returns the server info from the last call to GetServerInfo,
+// without making a request
+func (s *Server) LastServerInfo() api.ServerInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.info
+}
+
+// GetServerRoot fetches the server's capabilities, which are reported by the
+// root endpoint but not /media/providers
+func (s *Server) GetServerRoot() (*api.ServerInfoResponse, error) {
+	return httpRequest[api.ServerInfoResponse](s.httpClient, http.MethodGet, fmt.Sprintf(RootURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetButlerTasks() (*api.ButlerResponse, error) {
+	return httpRequest[api.ButlerResponse](s.httpClient, http.MethodGet, fmt.Sprintf(ButlerURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetUpdaterStatus() (*api.UpdaterStatusResponse, error) {
+	return httpRequest[api.UpdaterStatusResponse](s.httpClient, http.MethodGet, fmt.Sprintf(UpdaterURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetMyPlexAccount() (*api.MyPlexAccountResponse, error) {
+	return httpRequest[api.MyPlexAccountResponse](s.httpClient, http.MethodGet, fmt.Sprintf(MyPlexAccountURI, s.BaseURL), s.headers)
+}
+
 func (s *Server) GetSessionStatus() (*api.SessionList, error) {
 	return httpRequest[api.SessionList](s.httpClient, http.MethodGet, fmt.Sprintf(StatusURI, s.BaseURL), s.headers)
 }
 
+// GetHistory fetches a page of the watch history, optionally filtered by
+// Plex filters such as "viewedAt>"
+func (s *Server) GetHistory(filters url.Values, start, size int) (*api.HistoryResponse, error) {
+	headers := map[string]string{
+		"X-Plex-Container-Start": strconv.Itoa(start),
+		"X-Plex-Container-Size":  strconv.Itoa(size),
+	}
+	maps.Copy(headers, s.headers)
+
+	uri := fmt.Sprintf(HistoryURI, s.BaseURL)
+	if len(filters) > 0 {
+		uri += "?" + filters.Encode()
+	}
+	return httpRequest[api.HistoryResponse](s.httpClient, http.MethodGet, uri, headers)
+}
+
+func (s *Server) GetAccounts() (*api.AccountListResponse, error) {
+	return httpRequest[api.AccountListResponse](s.httpClient, http.MethodGet, fmt.Sprintf(AccountsURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetDevices() (*api.ClientDeviceListResponse, error) {
+	return httpRequest[api.ClientDeviceListResponse](s.httpClient, http.MethodGet, fmt.Sprintf(DevicesURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetTranscodeSessions() (*api.TranscodeSessionList, error) {
+	return httpRequest[api.TranscodeSessionList](s.httpClient, http.MethodGet, fmt.Sprintf(TranscodeURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetConversionQueue() (*api.PlayQueueResponse, error) {
+	return httpRequest[api.PlayQueueResponse](s.httpClient, http.MethodGet, fmt.Sprintf(ConversionQueueURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetResourceStatistics() (*api.ResourceStatisticsResponse, error) {
+	return httpRequest[api.ResourceStatisticsResponse](s.httpClient, http.MethodGet, fmt.Sprintf(ResourcesURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetBandwidthStatistics() (*api.BandwidthStatisticsResponse, error) {
+	return httpRequest[api.BandwidthStatisticsResponse](s.httpClient, http.MethodGet, fmt.Sprintf(BandwidthURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetDVRs() (*api.DVRListResponse, error) {
+	return httpRequest[api.DVRListResponse](s.httpClient, http.MethodGet, fmt.Sprintf(DVRsURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetSubscriptions() (*api.SubscriptionListResponse, error) {
+	return httpRequest[api.SubscriptionListResponse](s.httpClient, http.MethodGet, fmt.Sprintf(SubscriptionsURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetScheduledRecordings() (*api.GrabOperationListResponse, error) {
+	return httpRequest[api.GrabOperationListResponse](s.httpClient, http.MethodGet, fmt.Sprintf(ScheduledURI, s.BaseURL), s.headers)
+}
+
 func (s *Server) GetLibrary() (*api.LibraryResponse, error) {
 	return httpRequest[api.LibraryResponse](s.httpClient, http.MethodGet, fmt.Sprintf(LibraryURI, s.BaseURL), s.headers)
 }
 
-func (s *Server) GetSectionSize(id int) (int, error) {
-	// We don't want to get every item in the library section
-	// these headers make sure we only get metadata
+func (s *Server) GetMediaStatistics() (*api.MediaStatisticsResponse, error) {
+	return httpRequest[api.MediaStatisticsResponse](s.httpClient, http.MethodGet, fmt.Sprintf(MediaStatisticsURI, s.BaseURL), s.headers)
+}
+
+func (s *Server) GetActivities() (*api.ActivityResponse, error) {
+	return httpRequest[api.ActivityResponse](s.httpClient, http.MethodGet, fmt.Sprintf(ActivitiesURI, s.BaseURL), s.headers)
+}
+
+// GetSectionItems fetches a page of items from a library section, optionally
+// filtered by Plex section filters such as "type" or "unwatched".
+func (s *Server) GetSectionItems(id int, filters url.Values, start, size int) (*api.SectionResponse, error) {
+	return s.getItems(fmt.Sprintf(SectionURI, s.BaseURL, id), filters, start, size)
+}
+
+// GetSectionSize returns the number of items in a library section matching
+// the filters, or the number of top-level items if filters is nil.
+func (s *Server) GetSectionSize(id int, filters url.Values) (int, error) {
+	return s.getSize(fmt.Sprintf(SectionURI, s.BaseURL, id), filters)
+}
+
+// GetCollectionCount returns the number of collections in a library section
+func (s *Server) GetCollectionCount(id int) (int, error) {
+	return s.getSize(fmt.Sprintf(CollectionsURI, s.BaseURL, id), nil)
+}
+
+// GetPlaylistCount returns the number of playlists of a type ("video",
+// "audio" or "photo"), either smart or manual
+func (s *Server) GetPlaylistCount(playlistType string, smart bool) (int, error) {
+	filters := url.Values{"playlistType": {playlistType}, "smart": {"0"}}
+	if smart {
+		filters.Set("smart", "1")
+	}
+	return s.getSize(fmt.Sprintf(PlaylistsURI, s.BaseURL), filters)
+}
+
+// getItems fetches a page of the items listed at a url, such as a library
+// section or the playlists, optionally filtered by Plex filters.
+func (s *Server) getItems(uri string, filters url.Values, start, size int) (*api.SectionResponse, error) {
 	headers := map[string]string{
-		"X-Plex-Container-Start": "0",
-		"X-Plex-Container-Size":  "0",
+		"X-Plex-Container-Start": strconv.Itoa(start),
+		"X-Plex-Container-Size":  strconv.Itoa(size),
 	}
 	maps.Copy(headers, s.headers)
 
-	url := fmt.Sprintf(SectionURI, s.BaseURL, id)
-	resp, err := httpRequest[api.SectionResponse](s.httpClient, http.MethodGet, url, headers)
+	if len(filters) > 0 {
+		uri += "?" + filters.Encode()
+	}
+	return httpRequest[api.SectionResponse](s.httpClient, http.MethodGet, uri, headers)
+}
+
+// getSize returns the number of items listed at a url matching the filters
+func (s *Server) getSize(uri string, filters url.Values) (int, error) {
+	// We don't want to get every item in the list
+	// a container size of 0 makes sure we only get metadata
+	resp, err := s.getItems(uri, filters, 0, 0)
 	if err != nil {
 		return -1, err
 	}
@@ -1,20 +1,82 @@
 package plex
 
+import "time"
+
 type ServerMetric struct {
-	Version        string
-	Platform       string
-	ActiveSessions int
-	Players        []PlayerMetric
-	Libraries      []LibraryMetric
+	Version                       string
+	Platform                      string
+	PlatformVersion               string
+	PlexPass                      bool
+	TranscoderActiveVideoSessions int
+	Capabilities                  map[string]bool
+	Features                      []FeatureMetric
+	ActiveSessions                int
+	LANBandwidth                  int
+	WANBandwidth                  int
+	Sessions                      []SessionMetric
+	Players                       []PlayerMetric
+	Plays                         []PlayMetric
+	States                        []StateMetric
+	BufferingCount                float64
+	Libraries                     []LibraryMetric
+	Playlists                     []PlaylistMetric
+}
+
+type FeatureMetric struct {
+	Provider string
+	Feature  string
+}
+
+type PlaylistMetric struct {
+	Type  string
+	Smart bool
+	Count int
 }
 
 type LibraryMetric struct {
-	Name string
-	Type string
-	Size int
+	Name        string
+	Type        string
+	Size        int
+	Collections *int
+	Items       map[string]int
+	NewestItem  int64
+	ItemsAdded  float64
+	Stats       *SectionStats
+
+	UUID       string
+	Agent      string
+	Scanner    string
+	Language   string
+	Refreshing bool
+	ScannedAt  int64
+	Locations  []string
+}
+
+type SessionMetric struct {
+	ID               string
+	Device           string
+	Platform         string
+	Location         string
+	Bandwidth        int
+	Decision         string
+	MediaType        string
+	SourceResolution string
+	TargetResolution string
+}
+
+type PlayMetric struct {
+	PlayLabels
+	Plays        float64
+	WatchSeconds float64
+}
+
+type StateMetric struct {
+	State   string
+	Seconds float64
 }
 
 type PlayerMetric struct {
+	User     string
 	Device   string
 	Platform string
 	Profile  string
@@ -23,3 +85,112 @@ type PlayerMetric struct {
 	Relayed  string
 	Secure   string
 }
+
+type TranscodeMetric struct {
+	Key           string
+	VideoDecision string
+	AudioDecision string
+	HwDecoding    string
+	HwEncoding    string
+	Container     string
+	Speed         float64
+	Throttled     bool
+}
+
+type ResourceMetric struct {
+	HostCPU       float64
+	ProcessCPU    float64
+	HostMemory    float64
+	ProcessMemory float64
+}
+
+type BandwidthMetric struct {
+	BandwidthLabels
+	Bytes float64
+}
+
+type ActivityMetric struct {
+	Active []ActivityProgressMetric
+	Seen   map[string]float64
+}
+
+type ActivityProgressMetric struct {
+	Type     string
+	Title    string
+	Progress int
+}
+
+type ButlerTaskMetric struct {
+	Name     string
+	Title    string
+	Enabled  bool
+	Interval time.Duration
+
+	// Running is only known for tasks which report an activity
+	ReportsActivity bool
+	Running         bool
+}
+
+type UpdateMetric struct {
+	Source           string
+	Version          string
+	CandidateVersion string
+	Available        bool
+}
+
+type RemoteAccessMetric struct {
+	MappingState  string
+	MappingError  string
+	SignInState   string
+	PublicAddress string
+	PublicPort    string
+}
+
+type LibraryHealthMetric struct {
+	Name   string
+	Type   string
+	Issues map[string]int
+}
+
+type HistoryMetric struct {
+	HistoryLabels
+	Plays float64
+}
+
+type MediaStatsMetric struct {
+	MediaStatsLabels
+	Plays        float64
+	WatchSeconds float64
+}
+
+type LiveTVMetric struct {
+	DVRs           []DVRMetric
+	Devices        []DVRDeviceMetric
+	RecordingRules int
+	Recordings     map[string]int
+	Tuners         int
+	// Conflicts can only be counted when the number of tuners is known
+	Conflicts int
+}
+
+type DVRMetric struct {
+	Key      string
+	Lineup   string
+	Language string
+}
+
+type DVRDeviceMetric struct {
+	DVR         string
+	Device      string
+	Make        string
+	Model       string
+	Up          bool
+	Tuners      int
+	TunersInUse int
+}
+
+type ConversionMetric struct {
+	Queued     int
+	QueueBytes int64
+	Processing int
+}
@@ -0,0 +1,140 @@
+package plex
+
+import (
+	"sync"
+	"time"
+)
+
+// PlayLabels identifies the set of counters a play is accounted against
+type PlayLabels struct {
+	Section   string
+	MediaType string
+	User      string
+}
+
+// sessionSample is the state of a single session seen in a poll
+type sessionSample struct {
+	Key    string
+	State  string
+	Labels PlayLabels
+}
+
+type trackedSession struct {
+	labels   PlayLabels
+	state    string
+	lastSeen time.Time
+}
+
+// sessionTracker remembers sessions between polls of the session list so
+// that plays can be counted once the session has ended, and time spent in
+// each player state can be accumulated across polls.
+type sessionTracker struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	sessions  map[string]*trackedSession
+	plays     map[PlayLabels]float64
+	watched   map[PlayLabels]float64
+	states    map[string]float64
+	buffering float64
+}
+
+func newSessionTracker() *sessionTracker {
+	return &sessionTracker{
+		now:      time.Now,
+		sessions: make(map[string]*trackedSession),
+		plays:    make(map[PlayLabels]float64),
+		watched:  make(map[PlayLabels]float64),
+		states:   make(map[string]float64),
+	}
+}
+
+// Update records the sessions seen in a poll. Time since the previous poll
+// is accounted to each session's previous state, and any session no longer
+// present is counted as a completed play.
+func (t *sessionTracker) Update(samples []sessionSample) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	seen := make(map[string]bool, len(samples))
+	for _, sample := range samples {
+		seen[sample.Key] = true
+
+		s, ok := t.sessions[sample.Key]
+		if !ok {
+			t.sessions[sample.Key] = &trackedSession{
+				labels:   sample.Labels,
+				state:    sample.State,
+				lastSeen: now,
+			}
+			if sample.State == "buffering" {
+				t.buffering++
+			}
+			continue
+		}
+
+		t.account(s, now)
+		if sample.State == "buffering" && s.state != "buffering" {
+			t.buffering++
+		}
+		s.state = sample.State
+		s.lastSeen = now
+	}
+
+	for key, s := range t.sessions {
+		if !seen[key] {
+			t.plays[s.labels]++
+			delete(t.sessions, key)
+		}
+	}
+}
+
+// account adds the time elapsed since a session was last seen to the
+// counters for the state it was in.
+func (t *sessionTracker) account(s *trackedSession, now time.Time) {
+	elapsed := now.Sub(s.lastSeen).Seconds()
+	t.states[s.state] += elapsed
+	if s.state == "playing" {
+		t.watched[s.labels] += elapsed
+	}
+}
+
+// Plays returns the cumulative play counts and watch time
+func (t *sessionTracker) Plays() []PlayMetric {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	labels := make(map[PlayLabels]bool, len(t.plays)+len(t.watched))
+	for l := range t.plays {
+		labels[l] = true
+	}
+	for l := range t.watched {
+		labels[l] = true
+	}
+
+	metrics := make([]PlayMetric, 0, len(labels))
+	for l := range labels {
+		metrics = append(metrics, PlayMetric{
+			PlayLabels:   l,
+			Plays:        t.plays[l],
+			WatchSeconds: t.watched[l],
+		})
+	}
+	return metrics
+}
+
+// States returns the cumulative time spent by sessions in each player state,
+// and the number of times a session has started buffering.
+func (t *sessionTracker) States() ([]StateMetric, float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	metrics := make([]StateMetric, 0, len(t.states))
+	for state, seconds := range t.states {
+		metrics = append(metrics, StateMetric{
+			State:   state,
+			Seconds: seconds,
+		})
+	}
+	return metrics, t.buffering
+}
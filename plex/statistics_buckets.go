@@ -0,0 +1,41 @@
+package plex
+
+// bucketDeltas remembers the statistics buckets reported in the last poll,
+// so that each bucket is counted as it accumulates rather than once it is
+// complete. Plex reports statistics in buckets of a fixed timespan, and the
+// newest buckets keep growing until their timespan ends.
+type bucketDeltas[K comparable, B any] struct {
+	key    func(B) K
+	at     func(B) int64
+	last   map[K]B
+	oldest int64
+}
+
+// newBucketDeltas creates a bucketDeltas identifying buckets by key, which
+// must not include the bucket's values
+func newBucketDeltas[K comparable, B any](key func(B) K, at func(B) int64) *bucketDeltas[K, B] {
+	return &bucketDeltas[K, B]{key: key, at: at}
+}
+
+// update passes each bucket to ingest, along with the same bucket from the
+// last poll, or nil if it is new. New buckets older than the last poll's
+// oldest bucket have already been counted and dropped out of the last poll,
+// so they are skipped.
+func (d *bucketDeltas[K, B]) update(buckets []B, ingest func(b B, previous *B)) {
+	last := make(map[K]B, len(buckets))
+	var oldest int64
+	for i, b := range buckets {
+		k, at := d.key(b), d.at(b)
+		last[k] = b
+		if i == 0 || at < oldest {
+			oldest = at
+		}
+
+		if previous, ok := d.last[k]; ok {
+			ingest(b, &previous)
+		} else if len(d.last) == 0 || at >= d.oldest {
+			ingest(b, nil)
+		}
+	}
+	d.last, d.oldest = last, oldest
+}
@@ -0,0 +1,44 @@
+package plex
+
+import (
+	"testing"
+
+	"github.com/frebib/plex-exporter/plex/api"
+)
+
+func TestMediaStatsTrackerDeltas(t *testing.T) {
+	const day = 24 * 60 * 60
+	label := func(s api.MediaStatistic) MediaStatsLabels {
+		return MediaStatsLabels{MediaType: metadataTypeNames[s.MetadataType]}
+	}
+	bucket := func(at int64, count int) api.MediaStatistic {
+		return api.MediaStatistic{At: at, MetadataType: api.MetadataTypeMovie, Count: count, Duration: int64(count) * 100}
+	}
+
+	polls := []struct {
+		name   string
+		stats  []api.MediaStatistic
+		plays  float64
+		watchS float64
+	}{
+		{"first poll counts every bucket", []api.MediaStatistic{bucket(day, 2)}, 2, 200},
+		{"unchanged bucket isn't counted again", []api.MediaStatistic{bucket(day, 2)}, 2, 200},
+		{"growth of the newest bucket is counted", []api.MediaStatistic{bucket(day, 3)}, 3, 300},
+		{"new bucket is counted", []api.MediaStatistic{bucket(day, 3), bucket(2*day, 1)}, 4, 400},
+		{"dropped bucket isn't counted", []api.MediaStatistic{bucket(2*day, 1)}, 4, 400},
+		{"old bucket reappearing isn't counted", []api.MediaStatistic{bucket(day, 3), bucket(2*day, 2)}, 5, 500},
+	}
+
+	tracker := newMediaStatsTracker()
+	for _, p := range polls {
+		tracker.Update(p.stats, label)
+
+		totals := tracker.Totals()
+		if len(totals) != 1 {
+			t.Fatalf("%s: got %d totals, want 1", p.name, len(totals))
+		}
+		if totals[0].Plays != p.plays || totals[0].WatchSeconds != p.watchS {
+			t.Errorf("%s: got %v plays and %v watch time, want %v and %v", p.name, totals[0].Plays, totals[0].WatchSeconds, p.plays, p.watchS)
+		}
+	}
+}
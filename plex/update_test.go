@@ -0,0 +1,157 @@
+package plex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// stubReleases is a ReleaseChecker returning a fixed release, or an error
+type stubReleases struct {
+	version  string
+	err      error
+	platform string
+}
+
+func (r *stubReleases) LatestRelease(platform string) (string, error) {
+	r.platform = platform
+	return r.version, r.err
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.40.1.8227-c0dd5a73d", "1.40.1.8227-c0dd5a73d", 0},
+		{"1.40.1.8227-c0dd5a73d", "1.40.1.8227-ffffffff", 0},
+		{"1.40.2.8395-c67dce28e", "1.40.1.8227-c0dd5a73d", 1},
+		{"1.40.1.8227-c0dd5a73d", "1.40.2.8395-c67dce28e", -1},
+		{"1.41.0.8992-8463ad060", "1.40.10.8227-c0dd5a73d", 1},
+		{"1.40.1", "1.40.1.0", 0},
+		{"1.40.1.1", "1.40.1", 1},
+	}
+	for _, tt := range tests {
+		got := compareVersions(tt.a, tt.b)
+		if (got > 0) != (tt.want > 0) || (got < 0) != (tt.want < 0) {
+			t.Errorf("compareVersions(%q, %q) = %d, want sign of %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// newUpdateTestClient creates a client for a fake server running version,
+// with an updater offering candidate (or no release if empty)
+func newUpdateTestClient(t *testing.T, version, candidate string, releases ReleaseChecker) *PlexClient {
+	t.Helper()
+
+	updater := `{"MediaContainer":{}}`
+	if candidate != "" {
+		updater = fmt.Sprintf(`{"MediaContainer":{"Release":[{"version":%q,"state":"notify"}]}}`, candidate)
+	}
+	client := newTestClient(t, map[string]string{
+		"/media/providers": fmt.Sprintf(`{"MediaContainer":{"machineIdentifier":"abc","friendlyName":"test","version":%q,"platform":"MacOSX"}}`, version),
+		"/updater/status":  updater,
+	})
+	client.releases = releases
+	return client
+}
+
+func TestGetUpdateMetrics(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		candidate string
+		releases  *stubReleases
+		want      []UpdateMetric
+	}{
+		{
+			name:      "updater offers newer release",
+			version:   "1.40.1.8227-c0dd5a73d",
+			candidate: "1.40.2.8395-c67dce28e",
+			want: []UpdateMetric{
+				{Source: "updater", Version: "1.40.1.8227-c0dd5a73d", CandidateVersion: "1.40.2.8395-c67dce28e", Available: true},
+			},
+		},
+		{
+			name:      "updater offers installed release",
+			version:   "1.40.2.8395-c67dce28e",
+			candidate: "1.40.2.8395-c67dce28e",
+			want: []UpdateMetric{
+				{Source: "updater", Version: "1.40.2.8395-c67dce28e", CandidateVersion: "1.40.2.8395-c67dce28e"},
+			},
+		},
+		{
+			name:     "plex.tv has newer release",
+			version:  "1.40.1.8227-c0dd5a73d",
+			releases: &stubReleases{version: "1.41.0.8992-8463ad060"},
+			want: []UpdateMetric{
+				{Source: "updater", Version: "1.40.1.8227-c0dd5a73d"},
+				{Source: "plex.tv", Version: "1.40.1.8227-c0dd5a73d", CandidateVersion: "1.41.0.8992-8463ad060", Available: true},
+			},
+		},
+		{
+			name:      "plex.tv unavailable",
+			version:   "1.40.1.8227-c0dd5a73d",
+			candidate: "1.40.2.8395-c67dce28e",
+			releases:  &stubReleases{err: errors.New("plex.tv down")},
+			want: []UpdateMetric{
+				{Source: "updater", Version: "1.40.1.8227-c0dd5a73d", CandidateVersion: "1.40.2.8395-c67dce28e", Available: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var releases ReleaseChecker
+			if tt.releases != nil {
+				releases = tt.releases
+			}
+			client := newUpdateTestClient(t, tt.version, tt.candidate, releases)
+
+			got, err := client.GetUpdateMetrics()
+			if err != nil {
+				t.Fatalf("GetUpdateMetrics: %s", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetUpdateMetrics = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("GetUpdateMetrics[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+			if tt.releases != nil && tt.releases.platform != "MacOSX" {
+				t.Errorf("LatestRelease called with platform %q, want %q", tt.releases.platform, "MacOSX")
+			}
+		})
+	}
+}
+
+func TestPlexTVReleasesPlatform(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"computer":{"MacOS":{"id":"macos","version":"1.41.0.8992-8463ad060"},"Linux":{"id":"linux","version":"1.41.0.8994-f2c27da23"}}}`)
+	}))
+	defer ts.Close()
+
+	r := newPlexTVReleases()
+	r.url = ts.URL
+
+	for platform, want := range map[string]string{
+		"MacOSX": "1.41.0.8992-8463ad060",
+		"Linux":  "1.41.0.8994-f2c27da23",
+	} {
+		got, err := r.LatestRelease(platform)
+		if err != nil {
+			t.Errorf("LatestRelease(%q): %s", platform, err)
+		} else if got != want {
+			t.Errorf("LatestRelease(%q) = %q, want %q", platform, got, want)
+		}
+	}
+
+	if _, err := r.LatestRelease("Commodore64"); err == nil {
+		t.Error("LatestRelease of unknown platform did not fail")
+	}
+}
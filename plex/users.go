@@ -0,0 +1,79 @@
+package plex
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// OtherUserLabel is reported in place of a user's name once the maximum
+// number of distinct user labels has been reached.
+const OtherUserLabel = "other"
+
+// userLabeler maps Plex user names to metric label values. Names can be
+// hashed for privacy with a secret key, so they can't be recovered by
+// hashing a list of users, and the number of distinct users capped to bound
+// metric cardinality.
+type userLabeler struct {
+	mu     sync.Mutex
+	hash   bool
+	key    []byte
+	max    int
+	labels map[string]string
+}
+
+// randomUserLabelKey is used to hash user names when no key is configured.
+// It is shared by every server so a user has the same label on each.
+var randomUserLabelKey = sync.OnceValues(func() ([]byte, error) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	return key, err
+})
+
+// newUserLabeler creates a userLabeler. If names are hashed without a key, a
+// random key is used, so labels change when the exporter restarts.
+func newUserLabeler(hash bool, key string, max int) (*userLabeler, error) {
+	u := &userLabeler{
+		hash:   hash,
+		key:    []byte(key),
+		max:    max,
+		labels: make(map[string]string),
+	}
+	if hash && key == "" {
+		var err error
+		u.key, err = randomUserLabelKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+// Label returns the label value for the named user. A nil userLabeler
+// returns an empty label, as user labels are disabled.
+func (u *userLabeler) Label(name string) string {
+	if u == nil {
+		return ""
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if label, ok := u.labels[name]; ok {
+		return label
+	}
+	if u.max > 0 && len(u.labels) >= u.max {
+		return OtherUserLabel
+	}
+
+	label := name
+	if u.hash {
+		mac := hmac.New(sha256.New, u.key)
+		mac.Write([]byte(name))
+		label = hex.EncodeToString(mac.Sum(nil)[:6])
+	}
+	u.labels[name] = label
+	return label
+}
@@ -0,0 +1,70 @@
+package plex
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestUserLabelerHash(t *testing.T) {
+	a, err := newUserLabeler(true, "key-a", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newUserLabeler(true, "key-b", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	label := a.Label("alice")
+	if label != a.Label("alice") {
+		t.Error("label for the same user changed")
+	}
+	if label == b.Label("alice") {
+		t.Error("labels hashed with different keys are equal")
+	}
+
+	// The label must not be a plain hash of the name
+	sum := sha256.Sum256([]byte("alice"))
+	if label == hex.EncodeToString(sum[:6]) {
+		t.Error("label is an unkeyed hash of the name")
+	}
+}
+
+func TestUserLabelerRandomKey(t *testing.T) {
+	a, err := newUserLabeler(true, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newUserLabeler(true, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The random key is shared, so users have the same label on every server
+	if a.Label("alice") != b.Label("alice") {
+		t.Error("labels hashed with the random key differ between servers")
+	}
+
+	sum := sha256.Sum256([]byte("alice"))
+	if a.Label("alice") == hex.EncodeToString(sum[:6]) {
+		t.Error("label is an unkeyed hash of the name")
+	}
+}
+
+func TestUserLabelerMax(t *testing.T) {
+	u, err := newUserLabeler(false, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"alice", "bob"} {
+		if got := u.Label(name); got != name {
+			t.Errorf("Label(%q) = %q, want %q", name, got, name)
+		}
+	}
+	if got := u.Label("carol"); got != OtherUserLabel {
+		t.Errorf("Label past the maximum = %q, want %q", got, OtherUserLabel)
+	}
+	if got := u.Label("alice"); got != "alice" {
+		t.Errorf("Label of a known user past the maximum = %q, want %q", got, "alice")
+	}
+}